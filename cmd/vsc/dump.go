@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"valorantsecurecheck/pkg/cli"
+	"valorantsecurecheck/pkg/system"
+)
+
+// runDump implements `vsc dump [--json] <file.dmp>...`: the bugcheck and the
+// likely culprit driver of blue screen dumps.
+func runDump(args []string) int {
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, `usage: vsc dump [--json] <file.dmp>...   e.g. vsc dump C:\Windows\Minidump\101926-9875-01.dmp`)
+		return 2
+	}
+
+	code := 0
+	var dumps []system.CrashDump
+	for _, path := range fs.Args() {
+		d, err := system.ReadCrashDump(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			code = 1
+			if d.BugCheckCode == 0 {
+				continue
+			}
+		}
+		dumps = append(dumps, d)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(dumps)
+		return code
+	}
+	for _, d := range dumps {
+		cli.PrintCrashDump(d)
+	}
+	return code
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"valorantsecurecheck/pkg/cli"
+)
+
+// runExplain implements `vsc explain VAN 1067`.
+func runExplain(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: vsc explain <VAN|VAL> <code>   e.g. vsc explain VAN 1067")
+		return 2
+	}
+
+	code, err := cli.ParseErrorCode(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	ec, ok, err := cli.LookupErrorCode(code)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error code knowledge base:", err)
+		return 1
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s is not in the knowledge base yet.\n", code)
+		return 1
+	}
+
+	res, warns := collect()
+	if *flagVerbose {
+		for _, w := range warns {
+			fmt.Fprintln(os.Stderr, "[warn]", w)
+		}
+	}
+
+	cli.PrintExplain(ec, res.Checks)
+	return 0
+}
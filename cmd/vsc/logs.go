@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"valorantsecurecheck/pkg/cli"
+	"valorantsecurecheck/pkg/system"
+)
+
+// runLogs implements `vsc logs [--evtx file.evtx] [--json]`: the Vanguard,
+// Secure Boot, TPM and bugcheck events of an exported log, or of the live
+// System log when no file is given.
+func runLogs(args []string) int {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	evtx := fs.String("evtx", "", "Exported event log (.evtx) to read instead of the live System log")
+	asJSON := fs.Bool("json", false, "Print JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var rep system.EventLogReport
+	var err error
+	if *evtx != "" {
+		rep, err = system.AnalyzeEVTX(*evtx)
+	} else {
+		rep, err = system.GetEventLogReport()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if rep.Records == 0 {
+			return 1
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(rep)
+		return 0
+	}
+	cli.PrintEventLog(rep)
+	return 0
+}
@@ -21,6 +21,15 @@ var (
 	flagExitCode = flag.Bool("exit-code", false, "Exit 0 if Valorant-ready, 1 otherwise")
 	flagVerbose  = flag.Bool("v", false, "Print warnings to stderr (TUI hides them)")
 	flagShowVer  = flag.Bool("version", false, "Print version and exit")
+
+	flagMinBuild         = flag.Int("min-build", cli.MinWindowsBuild, "Minimum supported Windows build for the OSBuild check")
+	flagDriverBlocklist  = flag.String("driver-blocklist", "", "Vulnerable driver blocklist JSON (default: bundled list)")
+	flagVanguardManifest = flag.String("vanguard-manifest", "", "Known-good Vanguard file manifests JSON made with \"vsc manifest\"; the file integrity check is skipped without it (none are bundled)")
+	flagProgramData      = flag.String("programdata", "", "ProgramData folder holding Riot Games metadata (default: %ProgramData%)")
+	flagEventLogs        = flag.Bool("event-logs", false, "Export and scan the System event log for Vanguard and Secure Boot events (admin)")
+	flagMinidumps        = flag.Bool("minidumps", false, "Analyze the newest blue screen dumps in %SystemRoot%\\Minidump (admin)")
+	flagMitigationXML    = flag.String("mitigation-xml", "", "Exploit Protection export (Get-ProcessMitigation -RegistryConfigFilePath) to read instead of the live settings")
+	flagESP              = flag.String("esp", "", "Scan the EFI binaries of an ESP mounted at this path (e.g. S:\\ after mountvol S: /s)")
 )
 
 func main() {
@@ -30,57 +39,26 @@ func main() {
 		fmt.Println(buildinfo.Version)
 		return
 	}
+	cli.MinWindowsBuild = *flagMinBuild
+
+	switch flag.Arg(0) {
+	case "explain":
+		os.Exit(runExplain(flag.Args()[1:]))
+	case "manifest":
+		os.Exit(runManifest(flag.Args()[1:]))
+	case "logs":
+		os.Exit(runLogs(flag.Args()[1:]))
+	case "dump":
+		os.Exit(runDump(flag.Args()[1:]))
+	}
 
 	spawnBackgroundUpdater()
 
-	tpm, errTPM := system.GetTPMInfo()
-	sb, errSB := system.CheckSecureBoot()
-
-	keys, errKeys := system.GetSecureBootKeys(sb)
-	boot, errBoot := system.GetBootInfo()
-	disk, errDisk := system.GetBootDiskInfo()
-	virt, errVirt := system.GetVirtualizationInfo()
-	vg, errVG := system.GetVanguardInfo()
-	sys, errSYS := system.GetSystemInfo()
-
-	checks := cli.BuildChecks(tpm, sb, sys, boot, disk, virt, vg, keys)
-	res := cli.Result{
-		TPM:            tpm,
-		SecureBoot:     sb,
-		SecureBootKeys: keys,
-		Boot:           boot,
-		Disk:           disk,
-		Virt:           virt,
-		Vanguard:       vg,
-		System:         sys,
-		Checks:         checks,
-		CanRun:         cli.CanRunValorant(checks),
-	}
+	res, warns := collect()
 
 	if *flagVerbose {
-		if errTPM != nil {
-			fmt.Fprintln(os.Stderr, "[warn] TPM check error:", errTPM)
-		}
-		if errSB != nil {
-			fmt.Fprintln(os.Stderr, "[warn] SecureBoot check error:", errSB)
-		}
-		if errSYS != nil {
-			fmt.Fprintln(os.Stderr, "[warn] System info error:", errSYS)
-		}
-		if errKeys != nil {
-			fmt.Fprintln(os.Stderr, "[warn] SecureBoot keys check error:", errKeys)
-		}
-		if errBoot != nil {
-			fmt.Fprintln(os.Stderr, "[warn] Boot info error:", errBoot)
-		}
-		if errDisk != nil {
-			fmt.Fprintln(os.Stderr, "[warn] Disk info error:", errDisk)
-		}
-		if errVirt != nil {
-			fmt.Fprintln(os.Stderr, "[warn] Virtualization info error:", errVirt)
-		}
-		if errVG != nil {
-			fmt.Fprintln(os.Stderr, "[warn] Vanguard info error:", errVG)
+		for _, w := range warns {
+			fmt.Fprintln(os.Stderr, "[warn]", w)
 		}
 	}
 
@@ -107,6 +85,106 @@ func main() {
 	}
 }
 
+// collect runs every probe and builds the checks. Probe errors are returned as
+// human-readable warnings; the result is always usable.
+func collect() (cli.Result, []string) {
+	var warns []string
+	warn := func(what string, err error) {
+		if err != nil {
+			warns = append(warns, fmt.Sprintf("%s error: %v", what, err))
+		}
+	}
+
+	tpm, err := system.GetTPMInfo()
+	warn("TPM check", err)
+	sb, err := system.CheckSecureBoot()
+	warn("SecureBoot check", err)
+	sys, err := system.GetSystemInfo()
+	warn("System info", err)
+	keys, err := system.GetSecureBootKeys(sb)
+	warn("SecureBoot keys check", err)
+	boot, err := system.GetBootInfo()
+	warn("Boot info", err)
+	disk, err := system.GetBootDiskInfo()
+	warn("Disk info", err)
+	virt, err := system.GetVirtualizationInfo()
+	warn("Virtualization info", err)
+	vg, err := system.GetVanguardInfo()
+	warn("Vanguard info", err)
+	vgLogs, err := system.AnalyzeVanguardLogs(vg.InstallPath)
+	warn("Vanguard logs", err)
+	riot, err := system.GetRiotInstallInfo(*flagProgramData)
+	warn("Riot Client / Valorant install", err)
+	gameLogs, err := system.AnalyzeGameLogs("")
+	warn("Game logs", err)
+	integrity, err := system.CheckVanguardIntegrity(vg.InstallPath, vg.Version, *flagVanguardManifest)
+	warn("Vanguard integrity", err)
+	var exploit system.ExploitProtection
+	if *flagMitigationXML != "" {
+		exploit, err = system.ReadExploitProtection(*flagMitigationXML)
+	} else {
+		exploit, err = system.GetExploitProtection()
+	}
+	warn("Exploit protection", err)
+	drivers, err := system.ScanVulnerableDrivers(*flagDriverBlocklist)
+	warn("Driver scan", err)
+	conflicts, err := system.GetConflictInfo()
+	warn("Conflict scan", err)
+	dma, err := system.GetDMAInfo(virt)
+	warn("DMA protection", err)
+	bcd, err := system.GetBCDInfo()
+	warn("Boot configuration", err)
+	uefiBoot, err := system.GetUEFIBootOrder()
+	warn("UEFI boot entries", err)
+	bitLocker, err := system.GetBitLockerInfo()
+	warn("BitLocker", err)
+	var esp system.ESPInventory
+	if *flagESP != "" {
+		esp, err = system.GetESPInventory(*flagESP)
+		warn("ESP scan", err)
+	}
+	var eventLogs system.EventLogReport
+	if *flagEventLogs {
+		eventLogs, err = system.GetEventLogReport()
+		warn("Event log", err)
+	}
+	var minidumps system.MinidumpScan
+	if *flagMinidumps {
+		minidumps, err = system.ScanMinidumps("")
+		warn("Minidump scan", err)
+	}
+
+	res := cli.Result{
+		TPM:            tpm,
+		SecureBoot:     sb,
+		SecureBootKeys: keys,
+		Boot:           boot,
+		Disk:           disk,
+		Virt:           virt,
+		Vanguard:       vg,
+		System:         sys,
+		VanguardLogs:   vgLogs,
+		Integrity:      integrity,
+		Riot:           riot,
+		GameLogs:       gameLogs,
+		EventLogs:      eventLogs,
+		Minidumps:      minidumps,
+		Exploit:        exploit,
+		BitLocker:      bitLocker,
+		Drivers:        drivers,
+		Conflicts:      conflicts,
+		DMA:            dma,
+		BCD:            bcd,
+		UEFIBoot:       uefiBoot,
+		ESP:            esp,
+	}
+	res.Checks = cli.BuildChecks(res)
+	res.Notices = cli.BuildNotices(res)
+	res.FirmwareGuide = cli.BuildFirmwareGuide(sys, res.Checks)
+	res.CanRun = cli.CanRunValorant(res.Checks)
+	return res, warns
+}
+
 func spawnBackgroundUpdater() {
 	self, _ := os.Executable()
 	base := filepath.Dir(self)
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"valorantsecurecheck/pkg/system"
+)
+
+// runManifest implements `vsc manifest [install dir] [version]`: prints the
+// manifest entry of a known-good Vanguard install, to add to the file passed
+// with -vanguard-manifest (whose ignore list it uses).
+func runManifest(args []string) int {
+	var dir, version string
+	if len(args) > 0 {
+		dir = args[0]
+		if vi, err := system.ReadVersionInfo(filepath.Join(dir, "vgc.exe")); err == nil {
+			version = vi.FileVersion
+		}
+	} else {
+		vg, _ := system.GetVanguardInfo()
+		dir, version = vg.InstallPath, vg.Version
+	}
+	if len(args) > 1 {
+		version = args[1]
+	}
+	if dir == "" || version == "" {
+		fmt.Fprintln(os.Stderr, "usage: vsc manifest [install dir] [version]   (Vanguard install or version not found)")
+		return 2
+	}
+
+	m, err := system.BuildVanguardManifest(dir, version, *flagVanguardManifest)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(m)
+	return 0
+}
@@ -1,47 +1,183 @@
 package cli
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"valorantsecurecheck/pkg/system"
 )
 
-func BuildChecks(
-	tpm system.TPMInfo,
-	sb system.SecureBoot,
-	sys system.SystemInfo,
-	boot system.BootInfo,
-	disk system.DiskInfo,
-	virt system.VirtualizationInfo,
-	vg system.VanguardInfo,
-	keys system.SecureBootKeys,
-) map[string]bool {
+// MinWindowsBuild is the oldest Windows build accepted by the OSBuild check
+// (19045 = Windows 10 22H2, the last serviced Windows 10 release).
+var MinWindowsBuild = 19045
 
+// MinWindowsUBR is the oldest update (UBR) of MinWindowsBuild accepted; 0
+// accepts any.
+var MinWindowsUBR = 0
+
+// minWindowsVersion is MinWindowsBuild, with MinWindowsUBR when set.
+func minWindowsVersion() string {
+	if MinWindowsUBR > 0 {
+		return fmt.Sprintf("%d.%d", MinWindowsBuild, MinWindowsUBR)
+	}
+	return strconv.Itoa(MinWindowsBuild)
+}
+
+// minGameFreeBytes is the free space a Valorant patch usually needs.
+const minGameFreeBytes = 10 << 30
+
+// BuildChecks evaluates the probe results collected in res.
+func BuildChecks(res Result) map[string]bool {
 	sbKeysOK := false
-	if sb.Enabled {
+	if res.SecureBoot.Enabled {
 		// Secure Boot ON
 		sbKeysOK = true
-	} else if keys.Known {
-		sbKeysOK = keys.PK && keys.KEK && keys.DB
+	} else if res.SecureBootKeys.Known {
+		sbKeysOK = res.SecureBootKeys.PK && res.SecureBootKeys.KEK && res.SecureBootKeys.DB
 	}
 
 	return map[string]bool{
-		"TPM2":       tpm.Present && tpm.Ready && tpm.IsV2,
-		"SecureBoot": sb.Enabled,
-		"UEFI":       strings.EqualFold(boot.BIOSMode, "UEFI"),
-		"GPT":        strings.EqualFold(disk.PartitionStyle, "GPT"),
-		"Vanguard":   vg.Installed,
-		"VGCExists":  vg.VGC.Exists,
+		"TPM2":       res.TPM.Present && res.TPM.Ready && res.TPM.IsV2,
+		"SecureBoot": res.SecureBoot.Enabled,
+		"UEFI":       strings.EqualFold(res.Boot.BIOSMode, "UEFI"),
+		"GPT":        strings.EqualFold(res.Disk.PartitionStyle, "GPT"),
+		"Vanguard":   res.Vanguard.Installed,
+		"VGCExists":  res.Vanguard.VGC.Exists,
+		"OSBuild":    system.BuildAtLeast(res.System.OSBuild, res.System.OSUBR, MinWindowsBuild, MinWindowsUBR), // unknown build: don't block
+
+		"NoTestSigning":   !res.BCD.TestSigning,
+		"NoKernelDebug":   !res.BCD.Debug,
+		"IntegrityChecks": !res.BCD.NoIntegrityChecks,
 
 		"SBKeys":      sbKeysOK,
-		"VGCRunning":  vg.VGC.Running,
-		"VGKExists":   vg.VGK.Exists,
-		"HyperVOff":   !virt.HyperVEnabled,
-		"VBSDisabled": !virt.VBS_Enabled,
+		"VGCRunning":  res.Vanguard.VGC.Running,
+		"VGKExists":   res.Vanguard.VGK.Exists,
+		"HyperVOff":   !res.Virt.HyperVEnabled,
+		"VBSDisabled": !res.Virt.VBS_Enabled,
+
+		"VGCConfig":     !hasServiceIssue(res.Vanguard.ServiceIssues, "vgc"), // a missing service fails VGCExists only
+		"VGKConfig":     !hasServiceIssue(res.Vanguard.ServiceIssues, "vgk"),
+		"VGKLoaded":     res.Vanguard.VGK.Running,
+		"VGSigned":      signatureOK(res.Vanguard.VGCSignature) && signatureOK(res.Vanguard.VGKSignature),
+		"GameInstalled": len(res.Riot.Valorant) > 0 && res.Riot.Valorant[0].Installed,
+		"GameFreeSpace": len(res.Riot.Valorant) == 0 || res.Riot.Valorant[0].FreeBytes == 0 || res.Riot.Valorant[0].FreeBytes >= minGameFreeBytes,
+		"GameStable":    len(res.GameLogs.Fatal)+len(res.GameLogs.GPUDeviceLost) == 0,
+		"VGIntact":      !res.Integrity.Checked || len(res.Integrity.Missing)+len(res.Integrity.Modified) == 0,
+		"DEPOn":         !res.Exploit.Disabled("DEP"), // system-wide and for vgc.exe / the game
+		"CFGOn":         !res.Exploit.Disabled("ControlFlowGuard"),
+		"NoRestart":     res.Vanguard.State != system.VanguardStateRestartRequired,
+		"NoVulnDrivers": len(loadedDrivers(res.Drivers)) == 0,                        // installed but not loaded: notice only
+		"NoConflicts":   len(conflictsOfKind(res.Conflicts, "anticheat", true)) == 0, // shown as a warning only
+		"NoAVConflicts": len(conflictsOfKind(res.Conflicts, "antivirus", true)) == 0, // shown as a warning only
+		"DMAProtection": res.DMA.KernelDMAProtection,                                 // off: notice only
+		"WinBootFirst":  res.UEFIBoot.First == "" || res.UEFIBoot.WindowsFirst,       // entries unreadable: don't flag
+		"ESPLoaders":    len(espProblems(res.ESP)) == 0,                              // only with -esp
+
+		"CPU":       res.System.CPU != "",
+		"RAM>=4GiB": res.System.RAMGiB >= 4,
+	}
+}
+
+// Check states shown by the table and the TUI. A check is ok or failed unless
+// BuildNotices says otherwise.
+const (
+	StatusOK      = "ok"
+	StatusFail    = "fail"
+	StatusWarn    = "warn"    // passes, but something deserves attention
+	StatusInfo    = "info"    // informational, never a failure
+	StatusSkipped = "skipped" // nothing to check against
+)
+
+// BuildNotices returns the checks whose pass/fail value alone would mislead,
+// with the status to show instead.
+func BuildNotices(res Result) map[string]string {
+	n := map[string]string{}
+	if res.Checks["NoVulnDrivers"] && len(res.Drivers.Vulnerable) > 0 {
+		n["NoVulnDrivers"] = StatusWarn
+	}
+	// Other anti-cheats and antivirus products can get in the way, but do not
+	// stop Valorant by themselves. The checks still fail while they are
+	// running, so that explain can point at them.
+	if len(conflictsOfKind(res.Conflicts, "anticheat", false)) > 0 {
+		n["NoConflicts"] = StatusWarn
+	}
+	if len(conflictsOfKind(res.Conflicts, "antivirus", false)) > 0 {
+		n["NoAVConflicts"] = StatusWarn
+	}
+	// No manifest for this version: nothing was compared.
+	if !res.Integrity.Checked {
+		n["VGIntact"] = StatusSkipped
+	} else if res.Checks["VGIntact"] && len(res.Integrity.Unreadable) > 0 {
+		n["VGIntact"] = StatusWarn
+	}
+	// Vanguard does not require Kernel DMA Protection.
+	if !res.Checks["DMAProtection"] {
+		n["DMAProtection"] = StatusInfo
+	}
+	return n
+}
+
+// CheckStatus returns the status of a check: its notice, or ok/fail.
+func CheckStatus(res Result, key string) string {
+	if s, ok := res.Notices[key]; ok {
+		return s
+	}
+	if res.Checks[key] {
+		return StatusOK
+	}
+	return StatusFail
+}
+
+// loadedDrivers returns the vulnerable drivers the kernel is running.
+func loadedDrivers(scan system.DriverScan) []system.VulnerableDriver {
+	var out []system.VulnerableDriver
+	for _, d := range scan.Vulnerable {
+		if d.Loaded {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// conflictsOfKind returns the conflicting products of kind ("anticheat",
+// "antivirus"); with running set, only those whose service or real-time
+// protection is on.
+func conflictsOfKind(ci system.ConflictInfo, kind string, running bool) []system.ConflictProduct {
+	var out []system.ConflictProduct
+	for _, c := range ci.Conflicts {
+		if c.Kind == kind && (c.Running || !running) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func hasServiceIssue(issues []system.ServiceIssue, service string) bool {
+	for _, is := range issues {
+		if is.Service == service {
+			return true
+		}
+	}
+	return false
+}
+
+// signatureOK: not checked or missing (covered by the exists checks) counts as ok.
+func signatureOK(bs system.BinarySignature) bool {
+	return bs.Status == "" || bs.Status == system.SigStatusMissing || bs.SignatureOK()
+}
 
-		"CPU":       sys.CPU != "",
-		"RAM>=4GiB": sys.RAMGiB >= 4,
+// espProblems returns the ESP binaries the firmware would refuse with Secure Boot on.
+func espProblems(esp system.ESPInventory) []system.ESPBinary {
+	var out []system.ESPBinary
+	for _, b := range esp.Binaries {
+		switch b.SecureBoot {
+		case system.SBVerdictAllowed, system.SBVerdictUnknown:
+		default:
+			out = append(out, b)
+		}
 	}
+	return out
 }
 
 func CanRunValorant(checks map[string]bool) bool {
@@ -50,5 +186,9 @@ func CanRunValorant(checks map[string]bool) bool {
 		checks["UEFI"] &&
 		checks["GPT"] &&
 		checks["Vanguard"] &&
-		checks["VGCExists"]
+		checks["VGCExists"] &&
+		checks["OSBuild"] &&
+		checks["NoTestSigning"] &&
+		checks["NoKernelDebug"] &&
+		checks["IntegrityChecks"]
 }
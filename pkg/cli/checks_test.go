@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"testing"
+
+	"valorantsecurecheck/pkg/system"
+)
+
+func TestConflictChecks(t *testing.T) {
+	tests := []struct {
+		name             string
+		conflicts        []system.ConflictProduct
+		anticheat, av    bool   // check values
+		acStatus, avStat string // shown status
+	}{
+		{"none", nil, true, true, StatusOK, StatusOK},
+		{"anti-cheat installed", []system.ConflictProduct{{Name: "BattlEye", Kind: "anticheat"}},
+			true, true, StatusWarn, StatusOK},
+		{"anti-cheat running", []system.ConflictProduct{{Name: "FACEIT Anti-Cheat", Kind: "anticheat", Running: true}},
+			false, true, StatusWarn, StatusOK},
+		{"antivirus off", []system.ConflictProduct{{Name: "AVG", Kind: "antivirus"}},
+			true, true, StatusOK, StatusWarn},
+		{"antivirus on", []system.ConflictProduct{{Name: "Avast", Kind: "antivirus", Running: true}},
+			true, false, StatusOK, StatusWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Result{Conflicts: system.ConflictInfo{Conflicts: tt.conflicts}}
+			res.Checks = BuildChecks(res)
+			res.Notices = BuildNotices(res)
+			if res.Checks["NoConflicts"] != tt.anticheat || res.Checks["NoAVConflicts"] != tt.av {
+				t.Errorf("NoConflicts %v, NoAVConflicts %v", res.Checks["NoConflicts"], res.Checks["NoAVConflicts"])
+			}
+			if s := CheckStatus(res, "NoConflicts"); s != tt.acStatus {
+				t.Errorf("NoConflicts status %s, want %s", s, tt.acStatus)
+			}
+			if s := CheckStatus(res, "NoAVConflicts"); s != tt.avStat {
+				t.Errorf("NoAVConflicts status %s, want %s", s, tt.avStat)
+			}
+		})
+	}
+}
+
+// A running antivirus is the likely cause of VAN -81 it is listed for.
+func TestAntivirusCause(t *testing.T) {
+	ec, ok, err := LookupErrorCode("VAN -81")
+	if !ok {
+		t.Fatal("VAN -81 missing:", err)
+	}
+	res := Result{Conflicts: system.ConflictInfo{Conflicts: []system.ConflictProduct{{Name: "Avast", Kind: "antivirus", Running: true}}}}
+	checks := BuildChecks(res)
+	for _, k := range []string{"Vanguard", "VGCExists", "VGCRunning", "VGKExists", "VGKLoaded"} {
+		checks[k] = true
+	}
+	i := MostLikelyCause(ec, checks)
+	if i < 0 || ec.Causes[i].Checks[0] != "NoAVConflicts" {
+		t.Errorf("most likely cause %d", i)
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"valorantsecurecheck/pkg/system"
+)
+
+// PrintCrashDump prints the bugcheck and likely culprit of a crash dump.
+func PrintCrashDump(d system.CrashDump) {
+	fmt.Printf("%s (%s, %s)\n", d.File, d.DumpType, d.Time.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Bugcheck:   0x%08X %s\n", d.BugCheckCode, d.BugCheckName)
+	fmt.Printf("  Parameters: %016X %016X %016X %016X\n", d.BugCheckParams[0], d.BugCheckParams[1], d.BugCheckParams[2], d.BugCheckParams[3])
+	if d.FaultAddress != 0 {
+		module := d.FaultModule
+		if module == "" {
+			module = "unknown module"
+		}
+		fmt.Printf("  Fault:      %016X in %s\n", d.FaultAddress, module)
+	}
+	if len(d.StackModules) > 0 {
+		fmt.Printf("  On stack:   %s\n", strings.Join(d.StackModules, ", "))
+	}
+	if d.Culprit != "" {
+		fmt.Printf("  Culprit:    %s\n", d.Culprit)
+	}
+	if d.VanguardInvolved {
+		fmt.Println("  vgk.sys (Riot Vanguard) is involved: reinstall Vanguard and send this file to Riot support if it keeps happening.")
+	}
+	if d.Note != "" {
+		fmt.Println("  Note: " + d.Note)
+	}
+	fmt.Printf("  %d drivers loaded\n", len(d.Modules))
+}
@@ -0,0 +1,16 @@
+package cli
+
+import (
+	"fmt"
+
+	"valorantsecurecheck/pkg/system"
+)
+
+// PrintEventLog prints the relevant events of an event log, newest first.
+func PrintEventLog(rep system.EventLogReport) {
+	fmt.Printf("%s: %d records, %d relevant\n", rep.Source, rep.Records, len(rep.Events))
+	for _, ev := range rep.Events {
+		fmt.Printf("  %s  %-16s %s %d: %s\n", ev.Time.Format("2006-01-02 15:04:05"),
+			ev.Category, ev.Provider, ev.EventID, ev.Summary)
+	}
+}
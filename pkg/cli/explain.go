@@ -0,0 +1,139 @@
+package cli
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed explain_codes.json
+var explainCodesJSON []byte
+
+type ErrorCode struct {
+	Code   string  `json:"code"` // "VAN 1067", "VAL 57"
+	Title  string  `json:"title"`
+	Causes []Cause `json:"causes"`
+}
+
+// Cause is one likely reason for an error code. Checks are keys of BuildChecks:
+// the cause applies when at least one of them fails.
+type Cause struct {
+	Text   string   `json:"text"`
+	Checks []string `json:"checks"`
+	Fix    string   `json:"fix"`
+}
+
+var reErrorCode = regexp.MustCompile(`(?i)^(VAN|VAL)\s*[:#]?\s*(-?)\s*(\d{1,5})$`)
+
+var (
+	errorCodesOnce sync.Once
+	errorCodes     []ErrorCode
+	errorCodesErr  error
+)
+
+// ParseErrorCode accepts "VAN 1067", "VAN1067", "van #57", "VAL: 5" or the
+// two words split across args and returns the canonical "VAN 1067" form.
+// A dash is read as a separator ("VAN-1067" is VAN 1067) unless the negative
+// code is in the knowledge base ("VAN-81" and "VAN -81" are VAN -81).
+func ParseErrorCode(args []string) (string, error) {
+	in := strings.TrimSpace(strings.Join(args, " "))
+	m := reErrorCode.FindStringSubmatch(in)
+	if m == nil {
+		return "", fmt.Errorf("not a VAN/VAL error code: %q", in)
+	}
+	n, err := strconv.Atoi(m[3])
+	if err != nil {
+		return "", err
+	}
+	prefix := strings.ToUpper(m[1]) + " "
+	if m[2] == "-" && n != 0 {
+		neg := prefix + strconv.Itoa(-n)
+		if _, ok, _ := LookupErrorCode(neg); ok {
+			return neg, nil
+		}
+	}
+	return prefix + strconv.Itoa(n), nil
+}
+
+// LookupErrorCode returns the knowledge base entry for a canonical code.
+// The error is set when the embedded knowledge base does not parse.
+func LookupErrorCode(code string) (ErrorCode, bool, error) {
+	errorCodesOnce.Do(func() {
+		errorCodesErr = json.Unmarshal(explainCodesJSON, &errorCodes)
+	})
+	if errorCodesErr != nil {
+		return ErrorCode{}, false, errorCodesErr
+	}
+	for _, ec := range errorCodes {
+		if strings.EqualFold(ec.Code, code) {
+			return ec, true, nil
+		}
+	}
+	return ErrorCode{}, false, nil
+}
+
+// FailingChecks returns the checks of c that fail on this machine.
+func (c Cause) FailingChecks(checks map[string]bool) []string {
+	var out []string
+	for _, k := range c.Checks {
+		if v, ok := checks[k]; ok && !v {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// MostLikelyCause returns the index of the first cause with failing checks,
+// or -1 when every related check passes.
+func MostLikelyCause(ec ErrorCode, checks map[string]bool) int {
+	for i, c := range ec.Causes {
+		if len(c.FailingChecks(checks)) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// PrintExplain prints the causes of ec. When checks is nil, no live result is shown.
+func PrintExplain(ec ErrorCode, checks map[string]bool) {
+	fmt.Printf("%s — %s\n\n", ec.Code, ec.Title)
+
+	likely := -1
+	if checks != nil {
+		likely = MostLikelyCause(ec, checks)
+	}
+
+	for i, c := range ec.Causes {
+		fmt.Printf("%d. %s\n", i+1, c.Text)
+		if len(c.Checks) > 0 {
+			names := make([]string, 0, len(c.Checks))
+			for _, k := range c.Checks {
+				names = append(names, humanName(k))
+			}
+			status := ""
+			if checks != nil {
+				if failing := c.FailingChecks(checks); len(failing) > 0 {
+					status = "  ✗ failing on this PC"
+				} else {
+					status = "  ✓ OK on this PC"
+				}
+			}
+			fmt.Printf("   Checks: %s%s\n", strings.Join(names, ", "), status)
+		} else {
+			fmt.Println("   Checks: not detectable by this tool")
+		}
+		fmt.Printf("   Fix:    %s\n", c.Fix)
+		if i == likely {
+			fmt.Println("   → most likely cause on this PC")
+		}
+		fmt.Println()
+	}
+
+	if checks != nil && likely < 0 {
+		fmt.Println("All related checks pass on this PC: the cause is probably outside what this tool can detect.")
+	}
+}
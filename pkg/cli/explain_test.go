@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseErrorCode(t *testing.T) {
+	for in, want := range map[string]string{
+		"VAN 1067":   "VAN 1067",
+		"VAN1067":    "VAN 1067",
+		"  van  57 ": "VAN 57",
+		"VAN #57":    "VAN 57",
+		"val:5":      "VAL 5",
+		"VAL: 59":    "VAL 59",
+		"VAN-1067":   "VAN 1067", // dash as separator
+		"VAL-5":      "VAL 5",
+		"VAN -81":    "VAN -81", // known negative codes
+		"van-81":     "VAN -81",
+		"VAN -1":     "VAN -1",
+		"VAN 0081":   "VAN 81",
+	} {
+		got, err := ParseErrorCode(strings.Fields(in))
+		if err != nil || got != want {
+			t.Errorf("ParseErrorCode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "VAN", "1067", "VAX 1067", "VAN 123456", "VAN 10-67"} {
+		if got, err := ParseErrorCode(strings.Fields(in)); err == nil {
+			t.Errorf("ParseErrorCode(%q) = %q, want an error", in, got)
+		}
+	}
+}
+
+func TestMostLikelyCause(t *testing.T) {
+	ec := ErrorCode{Code: "VAN 1", Causes: []Cause{
+		{Text: "not detectable"},
+		{Text: "tpm", Checks: []string{"TPM2"}},
+		{Text: "secure boot or uefi", Checks: []string{"SecureBoot", "UEFI"}},
+	}}
+	for _, tc := range []struct {
+		name   string
+		checks map[string]bool
+		want   int
+	}{
+		{"all pass", map[string]bool{"TPM2": true, "SecureBoot": true, "UEFI": true}, -1},
+		{"first failing cause wins", map[string]bool{"TPM2": false, "SecureBoot": false, "UEFI": true}, 1},
+		{"any check of a cause", map[string]bool{"TPM2": true, "SecureBoot": true, "UEFI": false}, 2},
+		{"missing checks do not count", map[string]bool{}, -1},
+	} {
+		if got := MostLikelyCause(ec, tc.checks); got != tc.want {
+			t.Errorf("%s: MostLikelyCause = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+// Every check a cause refers to must be produced by BuildChecks, otherwise
+// the cause can never be reported as failing.
+func TestErrorCodeChecksExist(t *testing.T) {
+	checks := BuildChecks(Result{})
+	if _, _, err := LookupErrorCode(""); err != nil || len(errorCodes) == 0 {
+		t.Fatalf("knowledge base: %d codes, %v", len(errorCodes), err)
+	}
+	for _, ec := range errorCodes {
+		for _, c := range ec.Causes {
+			for _, k := range c.Checks {
+				if _, ok := checks[k]; !ok {
+					t.Errorf("%s %q: unknown check %q", ec.Code, c.Text, k)
+				}
+			}
+		}
+	}
+}
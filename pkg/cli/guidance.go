@@ -0,0 +1,208 @@
+package cli
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+	"unicode"
+
+	"valorantsecurecheck/pkg/system"
+)
+
+//go:embed firmware_guides.json
+var firmwareGuidesJSON []byte
+
+var (
+	guidesOnce     sync.Once
+	firmwareGuides []vendorGuide
+	genericGuide   vendorGuide
+)
+
+// builtinGenericGuide stands in when the embedded file does not parse or has
+// no "Generic" entry (TestFirmwareGuidesHaveGeneric guards against that).
+var builtinGenericGuide = vendorGuide{Vendor: "Generic", Steps: map[string][]string{
+	"tpm":        {"Enter the BIOS (DEL, F2 or F10 at boot)", "Enable the firmware TPM (AMD fTPM or Intel PTT)", "Save and exit"},
+	"csm":        {"Enter the BIOS", "Disable CSM / Legacy Support (boot mode UEFI only)", "Save and exit"},
+	"secureboot": {"Enter the BIOS", "Enable Secure Boot", "Save and exit"},
+	"keys":       {"Secure Boot > Key Management > Restore the default (factory) keys", "Save and exit"},
+}}
+
+type vendorGuide struct {
+	Vendor   string              `json:"vendor"`
+	Match    []string            `json:"match"`
+	Families []familyGuide       `json:"families"`
+	Steps    map[string][]string `json:"steps"`
+}
+
+type familyGuide struct {
+	Name  string              `json:"name"`
+	Match []string            `json:"match"`
+	Steps map[string][]string `json:"steps"`
+}
+
+// FirmwareGuide holds the BIOS steps for the failing checks of this machine.
+type FirmwareGuide struct {
+	Vendor string       `json:"vendor"`
+	Family string       `json:"family,omitempty"`
+	Topics []GuideTopic `json:"topics"`
+}
+
+type GuideTopic struct {
+	Topic string   `json:"topic"` // tpm / csm / secureboot / keys
+	Title string   `json:"title"`
+	Steps []string `json:"steps"`
+}
+
+// BuildFirmwareGuide picks the vendor (and family) from sys.Motherboard and
+// returns the steps for every failing firmware check. Topics is empty when
+// nothing needs to change in the BIOS.
+func BuildFirmwareGuide(sys system.SystemInfo, checks map[string]bool) FirmwareGuide {
+	guides, generic := loadFirmwareGuides()
+	words := boardWords(sys.Motherboard)
+	vg := generic
+	for _, g := range guides {
+		if matchBoard(words, sys.Motherboard, g.Match) {
+			vg = g
+			break
+		}
+	}
+
+	fg := FirmwareGuide{Vendor: vg.Vendor}
+	steps := vg.Steps
+	for _, f := range vg.Families {
+		if matchBoard(words, sys.Motherboard, f.Match) {
+			fg.Family = f.Name
+			steps = mergeSteps(vg.Steps, f.Steps)
+			break
+		}
+	}
+
+	cpu := strings.ToLower(sys.CPU)
+	tpmKey := "tpm"
+	switch {
+	case strings.Contains(cpu, "amd"):
+		tpmKey = "tpm_amd"
+	case strings.Contains(cpu, "intel"):
+		tpmKey = "tpm_intel"
+	}
+
+	add := func(topic, title string, keys ...string) {
+		for _, k := range keys {
+			if s := steps[k]; len(s) > 0 {
+				fg.Topics = append(fg.Topics, GuideTopic{Topic: topic, Title: title, Steps: s})
+				return
+			}
+		}
+	}
+
+	if !checks["TPM2"] {
+		add("tpm", "Enable TPM 2.0 (fTPM / PTT)", tpmKey, "tpm", "tpm_intel", "tpm_amd")
+	}
+	if !checks["UEFI"] {
+		add("csm", "Disable CSM / boot in UEFI mode", "csm")
+	}
+	if !checks["SecureBoot"] {
+		add("secureboot", "Enable Secure Boot", "secureboot")
+	}
+	if !checks["SBKeys"] {
+		add("keys", "Restore factory Secure Boot keys", "keys")
+	}
+	return fg
+}
+
+// FirmwareChangeSuggested reports whether any remediation we show means
+// changing firmware settings: BIOS steps or the UEFI boot order.
+func FirmwareChangeSuggested(res Result) bool {
+	return len(res.FirmwareGuide.Topics) > 0 || !res.Checks["WinBootFirst"]
+}
+
+// RecoveryKeyWarning returns the "back up your recovery key first" warning
+// when a firmware change is suggested and the OS volume is (or may be)
+// BitLocker-encrypted, "" otherwise.
+func RecoveryKeyWarning(res Result) string {
+	if !FirmwareChangeSuggested(res) {
+		return ""
+	}
+	bl := res.BitLocker
+	drive := bl.MountPoint
+	if drive == "" {
+		drive = "C:"
+	}
+	switch {
+	case !bl.Read:
+		return "BitLocker state unknown (run as administrator). If the drive is encrypted, back up your recovery key first (https://aka.ms/myrecoverykey): changing CSM/UEFI, Secure Boot, the boot order or clearing the TPM can make Windows ask for it at the next boot."
+	case !bl.Encrypted():
+		return ""
+	}
+	w := "BitLocker is on for " + drive + ": back up your recovery key first (https://aka.ms/myrecoverykey, or manage-bde -protectors -get " + drive + "). " +
+		"Changing CSM/UEFI, Secure Boot, the boot order or clearing the TPM will make Windows ask for it at the next boot."
+	if !bl.HasRecoveryPassword {
+		w += " This drive has no recovery password yet: add one with manage-bde -protectors -add " + drive + " -RecoveryPassword and save it before changing anything."
+	}
+	if bl.ProtectionOn {
+		w += " To skip the prompt, run Suspend-BitLocker -MountPoint " + drive + " -RebootCount 1 right before rebooting into the BIOS."
+	}
+	return w
+}
+
+// loadFirmwareGuides parses the embedded guides on first use.
+func loadFirmwareGuides() ([]vendorGuide, vendorGuide) {
+	guidesOnce.Do(func() {
+		firmwareGuides, genericGuide = parseFirmwareGuides(firmwareGuidesJSON)
+	})
+	return firmwareGuides, genericGuide
+}
+
+// parseFirmwareGuides returns the vendor guides and the "Generic" one, or the
+// built-in generic guide when the data is unusable.
+func parseFirmwareGuides(data []byte) ([]vendorGuide, vendorGuide) {
+	var guides []vendorGuide
+	if err := json.Unmarshal(data, &guides); err != nil {
+		return nil, builtinGenericGuide
+	}
+	for _, g := range guides {
+		if g.Vendor == "Generic" {
+			return guides, g
+		}
+	}
+	return guides, builtinGenericGuide
+}
+
+func boardWords(board string) []string {
+	return strings.FieldsFunc(strings.ToLower(board), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+}
+
+// matchBoard: short keys ("hp", "msi") must be a whole word, longer ones a substring.
+func matchBoard(words []string, board string, keys []string) bool {
+	low := strings.ToLower(board)
+	for _, k := range keys {
+		if len(k) > 3 && strings.Contains(low, k) {
+			return true
+		}
+		for _, w := range words {
+			if w == k {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mergeSteps(base, over map[string][]string) map[string][]string {
+	out := make(map[string][]string, len(base)+len(over))
+	for k, v := range base {
+		out[k] = v
+	}
+	// A family "tpm" entry replaces the vendor CPU-specific ones.
+	if _, ok := over["tpm"]; ok {
+		delete(out, "tpm_amd")
+		delete(out, "tpm_intel")
+	}
+	for k, v := range over {
+		out[k] = v
+	}
+	return out
+}
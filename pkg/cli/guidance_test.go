@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"valorantsecurecheck/pkg/system"
+)
+
+func TestBuildFirmwareGuideVendor(t *testing.T) {
+	failing := map[string]bool{"UEFI": true, "SBKeys": true}
+
+	for board, want := range map[string]string{
+		"ASUSTeK COMPUTER INC. ROG STRIX B550-F GAMING": "ASUS",
+		"Micro-Star International Co., Ltd. MAG B650":   "MSI",
+		"Some Unknown Board Vendor X1":                  "Generic",
+		"":                                              "Generic",
+	} {
+		fg := BuildFirmwareGuide(system.SystemInfo{Motherboard: board, CPU: "AMD Ryzen 7 5800X"}, failing)
+		if fg.Vendor != want {
+			t.Errorf("%q: vendor %q, want %q", board, fg.Vendor, want)
+		}
+		if len(fg.Topics) != 2 || fg.Topics[0].Topic != "tpm" || fg.Topics[1].Topic != "secureboot" {
+			t.Errorf("%q: topics %+v", board, fg.Topics)
+		}
+	}
+}
+
+func TestBuildFirmwareGuideNothingToChange(t *testing.T) {
+	checks := map[string]bool{"TPM2": true, "UEFI": true, "SecureBoot": true, "SBKeys": true}
+	if fg := BuildFirmwareGuide(system.SystemInfo{}, checks); len(fg.Topics) != 0 {
+		t.Fatalf("topics for a passing machine: %+v", fg.Topics)
+	}
+}
+
+func TestFirmwareGuidesHaveGeneric(t *testing.T) {
+	_, generic := parseFirmwareGuides(firmwareGuidesJSON)
+	if generic.Vendor != "Generic" {
+		t.Fatalf("generic guide is %+v", generic)
+	}
+	if reflect.DeepEqual(generic, builtinGenericGuide) {
+		t.Fatal("firmware_guides.json has no Generic entry: the built-in guide is used")
+	}
+	for _, k := range []string{"tpm_amd", "tpm_intel", "csm", "secureboot", "keys"} {
+		if len(generic.Steps[k]) == 0 {
+			t.Errorf("generic guide has no %q steps", k)
+		}
+	}
+}
+
+func TestParseFirmwareGuidesFallback(t *testing.T) {
+	for name, data := range map[string]string{
+		"not JSON":   `{`,
+		"no Generic": `[{"vendor": "ASUS", "match": ["asus"], "steps": {"csm": ["Boot > CSM"]}}]`,
+	} {
+		guides, generic := parseFirmwareGuides([]byte(data))
+		if generic.Vendor != "Generic" || len(generic.Steps["tpm"]) == 0 {
+			t.Errorf("%s: generic guide %+v", name, generic)
+		}
+		if name == "no Generic" && (len(guides) != 1 || guides[0].Vendor != "ASUS") {
+			t.Errorf("%s: vendor guides %+v", name, guides)
+		}
+	}
+
+	// The built-in guide covers every topic BuildFirmwareGuide can add.
+	for _, k := range []string{"tpm", "csm", "secureboot", "keys"} {
+		if len(builtinGenericGuide.Steps[k]) == 0 {
+			t.Errorf("built-in guide has no %q steps", k)
+		}
+	}
+}
@@ -11,6 +11,22 @@ type Result struct {
 	Virt           system.VirtualizationInfo
 	Vanguard       system.VanguardInfo
 	System         system.SystemInfo
+	VanguardLogs   system.VanguardLogSummary
+	Integrity      system.IntegrityReport
+	Riot           system.RiotInstallInfo
+	GameLogs       system.GameLogSummary
+	EventLogs      system.EventLogReport
+	Minidumps      system.MinidumpScan
+	Exploit        system.ExploitProtection
+	Drivers        system.DriverScan
+	Conflicts      system.ConflictInfo
+	DMA            system.DMAInfo
+	BCD            system.BCDInfo
+	UEFIBoot       system.UEFIBootOrder
+	ESP            system.ESPInventory
+	BitLocker      system.BitLockerInfo
 	Checks         map[string]bool
+	Notices        map[string]string // check -> Status*, see BuildNotices
+	FirmwareGuide  FirmwareGuide
 	CanRun         bool
 }
@@ -7,13 +7,21 @@ func PrintSummary(res Result) {
 		fmt.Println("READY — all checks passed")
 		return
 	}
-	for _, name := range []string{"TPM2", "SecureBoot", "CPU", "GPU", "RAM>=4GiB", "Motherboard", "Vanguard", "VGC"} {
+	failing := ""
+	for _, name := range []string{"TPM2", "SecureBoot", "CPU", "GPU", "RAM>=4GiB", "Motherboard", "OSBuild", "NoTestSigning", "NoKernelDebug", "IntegrityChecks", "Vanguard", "VGC"} {
 		if !res.Checks[name] {
-			fmt.Println("NOT READY — failing check:", humanName(name))
-			return
+			failing = name
+			break
 		}
 	}
-	fmt.Println("NOT READY")
+	if failing != "" {
+		fmt.Println("NOT READY — failing check:", humanName(failing))
+	} else {
+		fmt.Println("NOT READY")
+	}
+	if w := RecoveryKeyWarning(res); w != "" {
+		fmt.Println("WARNING —", w)
+	}
 }
 
 func humanName(k string) string {
@@ -28,6 +36,64 @@ func humanName(k string) string {
 		return "Riot Vanguard installed"
 	case "VGC":
 		return "Vanguard service (vgc)"
+	case "UEFI":
+		return "UEFI boot"
+	case "GPT":
+		return "Boot disk GPT"
+	case "SBKeys":
+		return "Secure Boot keys"
+	case "VGCExists":
+		return "vgc service exists"
+	case "VGCRunning":
+		return "vgc running"
+	case "VGKExists":
+		return "vgk exists"
+	case "VGCConfig":
+		return "vgc service configuration"
+	case "VGKConfig":
+		return "vgk driver configuration"
+	case "GameInstalled":
+		return "Valorant installed"
+	case "GameFreeSpace":
+		return "Free space for updates"
+	case "GameStable":
+		return "Last game session clean"
+	case "DEPOn":
+		return "DEP on (Exploit protection)"
+	case "CFGOn":
+		return "Control Flow Guard on"
+	case "VGIntact":
+		return "Vanguard files intact"
+	case "VGSigned":
+		return "Vanguard binaries signed"
+	case "VGKLoaded":
+		return "vgk loaded"
+	case "NoRestart":
+		return "No pending restart"
+	case "NoVulnDrivers":
+		return "No vulnerable drivers"
+	case "NoConflicts":
+		return "No conflicting anti-cheat running"
+	case "NoAVConflicts":
+		return "No interfering antivirus running"
+	case "OSBuild":
+		return "Windows build ≥ " + minWindowsVersion()
+	case "NoTestSigning":
+		return "Test signing off"
+	case "NoKernelDebug":
+		return "Kernel debugging off"
+	case "IntegrityChecks":
+		return "Driver integrity checks on"
+	case "WinBootFirst":
+		return "Windows Boot Manager first"
+	case "ESPLoaders":
+		return "ESP loaders signed"
+	case "DMAProtection":
+		return "Kernel DMA protection"
+	case "HyperVOff":
+		return "Hyper-V disabled"
+	case "VBSDisabled":
+		return "VBS disabled"
 	default:
 		return k
 	}
@@ -2,35 +2,88 @@ package cli
 
 import "fmt"
 
+// The label column fits the longest check name ("No conflicting software"),
+// the status column "– Not checked".
+const tableRule = "+--------------------------+---------------+"
+
 func PrintTable(res Result) {
-	fmt.Println("+----------------------+---------+")
-	fmt.Printf("| %-20s | %-7s |\n", "CHECK", "STATUS")
-	fmt.Println("+----------------------+---------+")
-
-	printRow("TPM 2.0", res.Checks["TPM2"])
-	printRow("Secure Boot", res.Checks["SecureBoot"])
-	printRow("Secure Boot Keys", res.Checks["SBKeys"])
-	printRow("BIOS Mode UEFI", res.Checks["BIOSUEFI"])
-	printRow("Boot Disk GPT", res.Checks["DiskGPT"])
-	printRow("Hyper-V Disabled", res.Checks["HyperVOff"])
-
-	printRow("Vanguard Installed", res.Checks["Vanguard"])
-	printRow("Service vgc exists", res.Checks["VGC"])
-	printRow("Service vgc running", res.Checks["VGCRunning"])
-	printRow("Service vgk exists", res.Checks["VGK"])
-
-	printRow("CPU", res.Checks["CPU"])
-	printRow("GPU", res.Checks["GPU"])
-	printRow("RAM ≥ 4 GiB", res.Checks["RAM>=4GiB"])
-	printRow("Motherboard", res.Checks["Motherboard"])
-
-	fmt.Println("+----------------------+---------+")
+	fmt.Println(tableRule)
+	fmt.Printf("| %-24s | %-13s |\n", "CHECK", "STATUS")
+	fmt.Println(tableRule)
+
+	printRow("TPM 2.0", CheckStatus(res, "TPM2"))
+	printRow("Secure Boot", CheckStatus(res, "SecureBoot"))
+	printRow("Secure Boot Keys", CheckStatus(res, "SBKeys"))
+	printRow("BIOS Mode UEFI", CheckStatus(res, "BIOSUEFI"))
+	printRow("Windows boots first", CheckStatus(res, "WinBootFirst"))
+	printRow("ESP loaders signed", CheckStatus(res, "ESPLoaders"))
+	printRow("Boot Disk GPT", CheckStatus(res, "DiskGPT"))
+	printRow("Hyper-V Disabled", CheckStatus(res, "HyperVOff"))
+	printRow("Windows build", CheckStatus(res, "OSBuild"))
+	printRow("Test signing off", CheckStatus(res, "NoTestSigning"))
+	printRow("Kernel debug off", CheckStatus(res, "NoKernelDebug"))
+	printRow("Integrity checks on", CheckStatus(res, "IntegrityChecks"))
+
+	printRow("Vanguard Installed", CheckStatus(res, "Vanguard"))
+	printRow("Service vgc exists", CheckStatus(res, "VGC"))
+	printRow("Service vgc running", CheckStatus(res, "VGCRunning"))
+	printRow("Service vgk exists", CheckStatus(res, "VGK"))
+	printRow("vgc config", CheckStatus(res, "VGCConfig"))
+	printRow("vgk config", CheckStatus(res, "VGKConfig"))
+	printRow("vgk loaded", CheckStatus(res, "VGKLoaded"))
+	printRow("Vanguard signed", CheckStatus(res, "VGSigned"))
+	printRow("Vanguard files intact", CheckStatus(res, "VGIntact"))
+	printRow("Valorant installed", CheckStatus(res, "GameInstalled"))
+	printRow("Free space (game)", CheckStatus(res, "GameFreeSpace"))
+	printRow("Last game session clean", CheckStatus(res, "GameStable"))
+	printRow("DEP on", CheckStatus(res, "DEPOn"))
+	printRow("Control Flow Guard on", CheckStatus(res, "CFGOn"))
+	printRow("No pending restart", CheckStatus(res, "NoRestart"))
+	printRow("No vulnerable drivers", CheckStatus(res, "NoVulnDrivers"))
+	printRow("No conflicting software", CheckStatus(res, "NoConflicts"))
+	printRow("No antivirus conflicts", CheckStatus(res, "NoAVConflicts"))
+	printRow("Kernel DMA protection", CheckStatus(res, "DMAProtection"))
+
+	printRow("CPU", CheckStatus(res, "CPU"))
+	printRow("GPU", CheckStatus(res, "GPU"))
+	printRow("RAM ≥ 4 GiB", CheckStatus(res, "RAM>=4GiB"))
+	printRow("Motherboard", CheckStatus(res, "Motherboard"))
+
+	fmt.Println(tableRule)
+
+	if w := RecoveryKeyWarning(res); w != "" {
+		fmt.Println()
+		fmt.Println("!! Back up your BitLocker recovery key first")
+		fmt.Println("   " + w)
+	}
+	printFirmwareGuide(res.FirmwareGuide)
 }
 
-func printRow(label string, ok bool) {
-	status := "✗ Not OK"
-	if ok {
-		status = "✓ OK"
+func printFirmwareGuide(fg FirmwareGuide) {
+	if len(fg.Topics) == 0 {
+		return
+	}
+	vendor := fg.Vendor
+	if fg.Family != "" {
+		vendor += " " + fg.Family
+	}
+	fmt.Println()
+	fmt.Printf("BIOS steps (%s):\n", vendor)
+	for _, t := range fg.Topics {
+		fmt.Println("  " + t.Title)
+		for i, s := range t.Steps {
+			fmt.Printf("    %d. %s\n", i+1, s)
+		}
 	}
-	fmt.Printf("| %-20s | %-7s |\n", label, status)
+}
+
+func printRow(label, status string) {
+	text := map[string]string{
+		StatusOK:      "✓ OK",
+		StatusFail:    "✗ Not OK",
+		StatusWarn:    "! Warning",
+		StatusInfo:    "• Info",
+		StatusSkipped: "– Not checked",
+	}[status]
+	fmt.Printf("| %-24s | %-13s |\n", label, text)
 }
@@ -2,11 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"valorantsecurecheck/pkg/system"
 )
 
 type clearReportMsg struct{}
@@ -157,6 +160,17 @@ func (m model) renderChecks(wrapW int) string {
 		}
 		return noStyle().Render("✗")
 	}
+	mark := func(key string) string {
+		switch CheckStatus(m.res, key) {
+		case StatusWarn:
+			return warnStyle().Render("!")
+		case StatusInfo:
+			return subStyle().Render("•")
+		case StatusSkipped:
+			return subStyle().Render("–")
+		}
+		return ok(m.res.Checks[key])
+	}
 
 	readyLine := ""
 	if m.res.CanRun {
@@ -172,14 +186,31 @@ func (m model) renderChecks(wrapW int) string {
 		fmt.Sprintf("%s Disk GPT", ok(m.res.Checks["GPT"])),
 		fmt.Sprintf("%s Vanguard installed", ok(m.res.Checks["Vanguard"])),
 		fmt.Sprintf("%s vgc service exists", ok(m.res.Checks["VGCExists"])),
+		fmt.Sprintf("%s Windows build", ok(m.res.Checks["OSBuild"])),
+		fmt.Sprintf("%s Test signing / debug off", ok(m.res.Checks["NoTestSigning"] && m.res.Checks["NoKernelDebug"] && m.res.Checks["IntegrityChecks"])),
 	}
 
 	diag := []string{
 		fmt.Sprintf("%s vgc running", ok(m.res.Checks["VGCRunning"])),
 		fmt.Sprintf("%s vgk exists", ok(m.res.Checks["VGKExists"])),
+		fmt.Sprintf("%s vgk loaded", ok(m.res.Checks["VGKLoaded"])),
+		fmt.Sprintf("%s Vanguard signature", ok(m.res.Checks["VGSigned"])),
+		fmt.Sprintf("%s Vanguard files intact", mark("VGIntact")),
+		fmt.Sprintf("%s Valorant installed", ok(m.res.Checks["GameInstalled"])),
+		fmt.Sprintf("%s Free space for updates", ok(m.res.Checks["GameFreeSpace"])),
+		fmt.Sprintf("%s Last game session clean", ok(m.res.Checks["GameStable"])),
+		fmt.Sprintf("%s DEP / Control Flow Guard on", ok(m.res.Checks["DEPOn"] && m.res.Checks["CFGOn"])),
+		fmt.Sprintf("%s No pending restart", ok(m.res.Checks["NoRestart"])),
+		fmt.Sprintf("%s No vulnerable drivers", mark("NoVulnDrivers")),
+		fmt.Sprintf("%s No conflicting software", mark("NoConflicts")),
+		fmt.Sprintf("%s No antivirus conflicts", mark("NoAVConflicts")),
+		fmt.Sprintf("%s Kernel DMA protection", mark("DMAProtection")),
+		fmt.Sprintf("%s vgc/vgk config", ok(m.res.Checks["VGCConfig"] && m.res.Checks["VGKConfig"])),
 		fmt.Sprintf("%s VBS disabled", ok(m.res.Checks["VBSDisabled"])),
 		fmt.Sprintf("%s Hyper-V disabled", ok(m.res.Checks["HyperVOff"])),
 		fmt.Sprintf("%s Secure Boot keys", ok(m.res.Checks["SBKeys"])),
+		fmt.Sprintf("%s Windows Boot Manager first", ok(m.res.Checks["WinBootFirst"])),
+		fmt.Sprintf("%s ESP loaders signed", ok(m.res.Checks["ESPLoaders"])),
 	}
 
 	block := strings.Join([]string{
@@ -235,8 +266,19 @@ func (m model) renderDetails(wrapW int, includeHardware bool) string {
 		lineKV("Secure Boot", fmt.Sprintf("%v (%s)", m.res.SecureBoot.Enabled, m.res.Boot.BIOSMode)),
 		lineKV("SB Keys", sbKeys),
 		lineKV("Disk", m.res.Disk.PartitionStyle),
-		lineKV("Vanguard", fmt.Sprintf("%v  v%s", m.res.Vanguard.Installed, m.res.Vanguard.Version)),
+		lineKV("Vanguard", fmt.Sprintf("%v  v%s  %s", m.res.Vanguard.Installed, m.res.Vanguard.Version, m.res.Vanguard.State)),
 		lineKV("Services", services),
+		lineKV("Signatures", signatureSummary(m.res.Vanguard)),
+		lineKV("Integrity", integritySummary(m.res.Integrity)),
+		lineKV("Mitigations", exploitSummary(m.res.Exploit)),
+		lineKV("BitLocker", bitLockerSummary(m.res.BitLocker)),
+		lineKV("Riot Client", riotClientSummary(m.res.Riot)),
+		lineKV("Valorant", valorantSummary(m.res.Riot)),
+		lineKV("Firmware", firmwareSummary(m.res.Boot)),
+		lineKV("Boot config", bcdSummary(m.res.BCD)),
+		lineKV("Boot order", bootOrderSummary(m.res.UEFIBoot)),
+		lineKV("VBS", vbsSummary(m.res.Virt)),
+		lineKV("DMA / IOMMU", fmt.Sprintf("protection=%v  iommu=%v", m.res.DMA.KernelDMAProtection, m.res.DMA.IOMMUAvailable)),
 	}
 
 	if !includeHardware {
@@ -251,23 +293,514 @@ func (m model) renderDetails(wrapW int, includeHardware bool) string {
 		lineKV("GPU", m.res.System.GPU),
 		lineKV("RAM", fmt.Sprintf("%d GiB", m.res.System.RAMGiB)),
 		lineKV("Board", m.res.System.Motherboard),
-		lineKV("OS", m.res.System.OS),
+		lineKV("OS", osLine(m.res.System)),
 	}
 
 	warns := []string{}
-	if m.res.Virt.VBS_Enabled {
-		warns = append(warns, "• VBS enabled: can cause Vanguard issues on some setups")
+	if !m.res.Checks["OSBuild"] {
+		warns = append(warns, fmt.Sprintf("• Windows build %d.%d is older than %s: run Windows Update before installing Vanguard", m.res.System.OSBuild, m.res.System.OSUBR, minWindowsVersion()))
+	}
+	if m.res.Boot.BootedViaCSM {
+		warns = append(warns, "• Windows booted through CSM (Legacy): Secure Boot stays off until the disk is GPT, CSM is disabled and the PC boots in UEFI mode")
+	}
+	if !m.res.Checks["WinBootFirst"] {
+		warns = append(warns, fmt.Sprintf("• The firmware boots %q before Windows Boot Manager: Windows may be chainloaded by another loader. Move Windows Boot Manager to the top of the BIOS boot order", m.res.UEFIBoot.First))
+	} else if cur, found := m.res.UEFIBoot.Entry(m.res.UEFIBoot.BootCurrent); found && !cur.WindowsBootManager {
+		warns = append(warns, fmt.Sprintf("• This boot used %q, not Windows Boot Manager: Secure Boot measurements may not cover Windows. Boot Windows directly from the firmware", cur.Description))
+	}
+	if m.res.Boot.FastStartup && !m.res.CanRun {
+		warns = append(warns, "• Fast Startup is on: use Restart (not Shut down) after BIOS or Vanguard changes")
+	}
+	if m.res.BCD.TestSigning {
+		warns = append(warns, "• Test signing is on: Vanguard refuses to start. Run `bcdedit /set testsigning off` as admin and restart")
+	}
+	if m.res.BCD.Debug {
+		warns = append(warns, "• Kernel debugging is on: run `bcdedit /debug off` as admin and restart")
+	}
+	if m.res.BCD.NoIntegrityChecks {
+		warns = append(warns, "• Driver integrity checks are off: run `bcdedit /set nointegritychecks off` as admin and restart")
 	}
+	warns = append(warns, vbsWarnings(m.res.Virt)...)
 	if m.res.Virt.HypervisorPresent && !m.res.Virt.HyperVEnabled {
 		warns = append(warns, "• Hypervisor present: possible WSL / Device Guard / VM")
 	}
+	if m.res.Vanguard.StateHint != "" {
+		warns = append(warns, "• "+m.res.Vanguard.StateHint)
+	}
+	for _, bs := range []system.BinarySignature{m.res.Vanguard.VGCSignature, m.res.Vanguard.VGKSignature} {
+		if !signatureOK(bs) {
+			warns = append(warns, fmt.Sprintf("• %s is %s: the install is damaged or was modified. Uninstall Riot Vanguard, restart and reinstall it from the Riot Client", bs.File, signatureText(bs)))
+		}
+	}
+	if in := m.res.Integrity; len(in.Missing)+len(in.Modified) > 0 {
+		warns = append(warns, fmt.Sprintf("• Vanguard files differ from the v%s install (missing: %s; modified: %s): uninstall Riot Vanguard, restart and reinstall it",
+			in.Version, listOrNone(in.Missing), listOrNone(in.Modified)))
+	}
+	if in := m.res.Integrity; len(in.Unreadable) > 0 {
+		warns = append(warns, fmt.Sprintf("• Could not read %s to verify it (locked or access denied): run as administrator to check it", listOrNone(in.Unreadable)))
+	}
+	for _, g := range m.res.Riot.Valorant {
+		if g.ShouldRepair {
+			warns = append(warns, fmt.Sprintf("• The Riot Client flagged the %s install for repair: open the Riot Client, Valorant settings, Repair", g.Patchline))
+		}
+	}
+	if !m.res.Checks["GameFreeSpace"] {
+		warns = append(warns, fmt.Sprintf("• Only %.1f GiB free on the Valorant drive: updates need about %d GiB", gib(m.res.Riot.Valorant[0].FreeBytes), minGameFreeBytes>>30))
+	}
+	if len(m.res.GameLogs.GPUDeviceLost) > 0 {
+		warns = append(warns, "• The GPU was lost during the last game session (device removed/hung): update or roll back the GPU driver and remove any GPU/CPU overclock")
+	}
+	for _, e := range m.res.EventLogs.Events {
+		if e.Category == system.EventCategoryVanguard {
+			warns = append(warns, "• The event log shows a Vanguard service failure ("+e.Summary+"): reinstall Vanguard and restart")
+			break
+		}
+	}
+	if m.res.Exploit.DEPPolicy == system.DEPAlwaysOff {
+		warns = append(warns, "• DEP is turned off for every program (bcdedit nx AlwaysOff): run \"bcdedit /set nx OptIn\" as administrator and restart")
+	}
+	for _, p := range m.res.Exploit.Problems {
+		warns = append(warns, "• Exploit protection: "+p+". Turn it back on (Windows Security > App & browser control > Exploit protection settings) and restart")
+	}
+	for _, d := range m.res.Minidumps.Dumps {
+		if d.VanguardInvolved {
+			warns = append(warns, "• A recent blue screen ("+d.BugCheckName+") points at vgk.sys: reinstall Vanguard and send the dump from the Minidump folder to Riot support if it repeats")
+			break
+		}
+	}
+	for _, is := range m.res.Vanguard.ServiceIssues {
+		warns = append(warns, "• "+is.Explain)
+	}
+	for i, ss := range []system.ServiceStatus{m.res.Vanguard.VGC, m.res.Vanguard.VGK} {
+		if ss.ConfigError != "" {
+			warns = append(warns, fmt.Sprintf("• Could not read the %s service key (%s): its configuration was not checked", []string{"vgc", "vgk"}[i], ss.ConfigError))
+		}
+	}
+	for _, b := range espProblems(m.res.ESP) {
+		warns = append(warns, fmt.Sprintf("• ESP loader %s is %s: Secure Boot blocks it, or the firmware skips to the next boot entry", b.Path, espVerdictText(b)))
+	}
+	for _, d := range m.res.Drivers.Vulnerable {
+		state := "installed, not loaded"
+		if d.Loaded {
+			state = "loaded"
+		}
+		warns = append(warns, fmt.Sprintf("• Vulnerable driver %s (%s, %s): %s", d.File, d.Vendor, state, d.Reason))
+	}
 	if len(warns) > 0 {
 		hw = append(hw, "", warnStyle().Render("Warnings"), wrapText(strings.Join(warns, "\n"), wrapW))
 	}
 
+	if w := RecoveryKeyWarning(m.res); w != "" {
+		hw = append(hw, "", warnStyle().Render("⚠ Back up your BitLocker recovery key first"), wrapText(w, wrapW))
+	}
+
+	if fg := m.res.FirmwareGuide; len(fg.Topics) > 0 {
+		vendor := fg.Vendor
+		if fg.Family != "" {
+			vendor += " " + fg.Family
+		}
+		lines := []string{}
+		for _, t := range fg.Topics {
+			lines = append(lines, t.Title)
+			for i, st := range t.Steps {
+				lines = append(lines, fmt.Sprintf("  %d. %s", i+1, st))
+			}
+		}
+		hw = append(hw, "", sectionStyle().Render("BIOS steps ("+vendor+")"), wrapText(strings.Join(lines, "\n"), wrapW))
+	}
+
+	if len(m.res.Conflicts.Conflicts) > 0 {
+		lines := []string{}
+		for _, c := range m.res.Conflicts.Conflicts {
+			state := "installed"
+			if c.Running {
+				state = "running"
+			}
+			lines = append(lines, fmt.Sprintf("• %s (%s, %s): %s", c.Name, c.Source, state, c.Note))
+		}
+		hw = append(hw, "", warnStyle().Render("Possible conflicts"), wrapText(strings.Join(lines, "\n"), wrapW))
+	}
+
+	if lg := m.res.VanguardLogs; lg.Found {
+		session := "unknown"
+		if !lg.SessionStart.IsZero() {
+			session = lg.SessionStart.Format("2006-01-02 15:04") + " → " + sessionEnd(lg.SessionStart, lg.SessionEnd)
+		}
+		codes := "none"
+		if len(lg.ErrorCodes) > 0 {
+			codes = strings.Join(lg.ErrorCodes, ", ")
+		}
+		driver := "no load failures"
+		if len(lg.DriverFailures) > 0 {
+			driver = fmt.Sprintf("%d failure(s): %s", lg.DriverFailureCount, lg.DriverFailures[len(lg.DriverFailures)-1])
+		}
+		hw = append(hw,
+			"",
+			sectionStyle().Render("Vanguard logs"),
+			"",
+			lineKV("Last session", session),
+			lineKV("Error codes", codes),
+			lineKV("Driver", driver),
+		)
+	}
+
+	if gl := m.res.GameLogs; gl.Found || len(gl.Crashes) > 0 {
+		session := "unknown"
+		if !gl.SessionStart.IsZero() {
+			session = gl.SessionStart.Format("2006-01-02 15:04")
+			if !gl.SessionEnd.IsZero() {
+				session += " → " + sessionEnd(gl.SessionStart, gl.SessionEnd)
+			}
+		}
+		lastOf := func(l []string) string {
+			if len(l) == 0 {
+				return "none"
+			}
+			return fmt.Sprintf("%d: %s", len(l), l[len(l)-1])
+		}
+		crash := "none"
+		if len(gl.Crashes) > 0 {
+			c := gl.Crashes[0]
+			crash = fmt.Sprintf("%d report(s), last %s %s", len(gl.Crashes), c.Time.Format("2006-01-02 15:04"), c.Error)
+		}
+		hw = append(hw,
+			"",
+			sectionStyle().Render("Game logs"),
+			"",
+			lineKV("Last session", session),
+			lineKV("Error codes", listOrNone(gl.ErrorCodes)),
+			lineKV("Fatal", lastOf(gl.Fatal)),
+			lineKV("GPU lost", lastOf(gl.GPUDeviceLost)),
+			lineKV("Anti-cheat", lastOf(gl.AntiCheat)),
+			lineKV("Crashes", crash),
+		)
+	}
+
+	if hv := m.res.Virt.Hypervisors; len(hv.Features)+len(hv.Products) > 0 {
+		lines := []string{}
+		for _, f := range hv.Features {
+			if !f.Enabled {
+				continue
+			}
+			what := "on"
+			if f.Hypervisor {
+				what = "on, starts the hypervisor"
+			}
+			lines = append(lines, fmt.Sprintf("• %s (%s): %s. Turning it off removes %s", f.Title, f.Name, what, f.Provides))
+		}
+		for _, p := range hv.Products {
+			state := "installed"
+			if p.Running {
+				state = "running"
+			}
+			lines = append(lines, fmt.Sprintf("• %s (%s) %s. %s", p.Name, p.Source, state, p.Note))
+		}
+		if len(lines) == 0 {
+			lines = append(lines, "• none enabled")
+		} else if len(hv.HypervisorFeaturesOn()) > 0 {
+			lines = append(lines, "Turn features off in optionalfeatures.exe (Turn Windows features on or off) or with Disable-WindowsOptionalFeature -Online -FeatureName <name>, then restart.")
+		}
+		hw = append(hw, "", sectionStyle().Render("Hypervisor features"), wrapText(strings.Join(lines, "\n"), wrapW))
+	}
+
+	if len(m.res.ESP.Binaries) > 0 {
+		lines := []string{}
+		for _, b := range m.res.ESP.Binaries {
+			signer := b.Signature.Signer
+			if signer == "" {
+				signer = "-"
+			}
+			lines = append(lines, fmt.Sprintf("• %s (%s) %s: %s", b.Path, b.Signature.Arch, signer, b.SecureBoot))
+		}
+		hw = append(hw, "", sectionStyle().Render("ESP loaders ("+m.res.ESP.Root+")"), wrapText(strings.Join(lines, "\n"), wrapW))
+	}
+
+	if ev := m.res.EventLogs.Events; len(ev) > 0 {
+		lines := []string{}
+		for _, e := range ev[:min(len(ev), 8)] {
+			lines = append(lines, fmt.Sprintf("• %s %s: %s", e.Time.Format("2006-01-02 15:04"), e.Category, e.Summary))
+		}
+		hw = append(hw, "", sectionStyle().Render("Event log ("+m.res.EventLogs.Source+")"), wrapText(strings.Join(lines, "\n"), wrapW))
+	}
+
+	if dumps := m.res.Minidumps.Dumps; len(dumps) > 0 {
+		lines := []string{}
+		for _, d := range dumps {
+			culprit := d.Culprit
+			if culprit == "" {
+				culprit = "unknown"
+			}
+			lines = append(lines, fmt.Sprintf("• %s %s (0x%X), culprit: %s", d.Time.Format("2006-01-02 15:04"), d.BugCheckName, d.BugCheckCode, culprit))
+		}
+		hw = append(hw, "", sectionStyle().Render("Blue screens"), wrapText(strings.Join(lines, "\n"), wrapW))
+	}
+
 	return strings.Join(append(main, hw...), "\n")
 }
 
+func osLine(s system.SystemInfo) string {
+	line := s.OS
+	if s.DisplayVersion != "" {
+		line += " " + s.DisplayVersion
+	}
+	if s.OSBuild > 0 {
+		line += fmt.Sprintf(" (build %d.%d", s.OSBuild, s.OSUBR)
+		if s.Architecture != "" {
+			line += ", " + s.Architecture
+		}
+		line += ")"
+	}
+	return line
+}
+
+// firmwareSummary: Windows cannot tell whether CSM is enabled in the
+// firmware, only whether it booted through it.
+func firmwareSummary(b system.BootInfo) string {
+	csm := "not used for this boot"
+	switch {
+	case b.BootedViaCSM:
+		csm = "booted via CSM"
+	case !b.FirmwareVarsRead:
+		csm = "unknown"
+	}
+	fast := "off"
+	if b.FastStartup {
+		fast = "on"
+	}
+	return fmt.Sprintf("CSM %s  •  Fast Startup %s", csm, fast)
+}
+
+func riotClientSummary(r system.RiotInstallInfo) string {
+	if r.ClientPath == "" {
+		return "not found"
+	}
+	if r.ClientVersion != "" {
+		return r.ClientPath + "  v" + r.ClientVersion
+	}
+	return r.ClientPath
+}
+
+func valorantSummary(r system.RiotInstallInfo) string {
+	if len(r.Valorant) == 0 {
+		return "not found"
+	}
+	var parts []string
+	for _, g := range r.Valorant {
+		s := g.Patchline + ": " + g.Path
+		if g.Version != "" {
+			s += "  v" + g.Version
+		}
+		if !g.Installed {
+			s += " (folder missing)"
+		} else if g.FreeBytes > 0 {
+			s += fmt.Sprintf(" (%.1f GiB free)", gib(g.FreeBytes))
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, "  •  ")
+}
+
+func gib(b uint64) float64 { return float64(b) / (1 << 30) }
+
+func integritySummary(in system.IntegrityReport) string {
+	switch {
+	case in.Version == "":
+		return "not checked"
+	case !in.Checked && in.ManifestSource == "bundled":
+		return "skipped: needs -vanguard-manifest (no manifests are bundled)"
+	case !in.Checked:
+		return "no manifest for v" + in.Version + " (" + in.ManifestSource + ")"
+	case len(in.Missing)+len(in.Modified)+len(in.Extra)+len(in.Unreadable) == 0:
+		return fmt.Sprintf("ok, %d files match v%s", in.Files, in.Version)
+	}
+	return fmt.Sprintf("%d missing, %d modified, %d extra, %d unreadable (v%s)",
+		len(in.Missing), len(in.Modified), len(in.Extra), len(in.Unreadable), in.Version)
+}
+
+func bitLockerSummary(bl system.BitLockerInfo) string {
+	switch {
+	case !bl.Read:
+		return "unknown (needs admin)"
+	case !bl.Encrypted():
+		return "off"
+	}
+	s := bl.VolumeStatus
+	if !bl.ProtectionOn {
+		s += ", protection suspended"
+	}
+	s += ", protectors: " + listOrNone(bl.Protectors)
+	if bl.HasTPMProtector() {
+		pcrs := make([]string, len(bl.PCRs))
+		for i, p := range bl.PCRs {
+			pcrs[i] = strconv.Itoa(p)
+		}
+		s += ", PCR " + listOrNone(pcrs)
+		if bl.PCR7Bound {
+			s += " (PCR7 bound)"
+		}
+	}
+	return s
+}
+
+func exploitSummary(ep system.ExploitProtection) string {
+	if !ep.Read {
+		if ep.DEPPolicy != "" {
+			return "DEP policy " + ep.DEPPolicy + ", mitigation policy not read"
+		}
+		return "not read"
+	}
+	var procs []string
+	for _, p := range ep.Processes {
+		procs = append(procs, p.Executable)
+	}
+	state := func(name string) string {
+		if ep.Disabled(name) {
+			return "off"
+		}
+		return "on"
+	}
+	dep := state("DEP")
+	if ep.DEPPolicy != "" {
+		dep += " (" + ep.DEPPolicy + ")"
+	}
+	return fmt.Sprintf("DEP %s, CFG %s, overrides: %s", dep, state("ControlFlowGuard"), listOrNone(procs))
+}
+
+func listOrNone(l []string) string {
+	if len(l) == 0 {
+		return "none"
+	}
+	return strings.Join(l, ", ")
+}
+
+func signatureSummary(v system.VanguardInfo) string {
+	if v.VGCSignature.Status == "" && v.VGKSignature.Status == "" {
+		return "not checked"
+	}
+	return fmt.Sprintf("vgc.exe: %s  •  vgk.sys: %s", signatureText(v.VGCSignature), signatureText(v.VGKSignature))
+}
+
+func signatureText(bs system.BinarySignature) string {
+	switch bs.Status {
+	case system.SigStatusRiot, system.SigStatusMicrosoft, system.SigStatusOther:
+		return "signed by " + bs.Signer
+	case system.SigStatusUntrusted:
+		return "signed by " + bs.Signer + " (untrusted chain: " + bs.Detail + ")"
+	case system.SigStatusInvalid:
+		return "signature invalid (" + bs.Detail + ")"
+	case system.SigStatusUnsigned:
+		return "unsigned"
+	case system.SigStatusMissing:
+		return "missing"
+	}
+	return "unknown"
+}
+
+func espVerdictText(b system.ESPBinary) string {
+	switch b.SecureBoot {
+	case system.SBVerdictRevoked:
+		return "revoked (listed in dbx)"
+	case system.SBVerdictNotTrusted:
+		return "signed by " + b.Signature.Issuer + ", which is not in db"
+	case system.SBVerdictInvalid:
+		return "damaged (" + b.Signature.Error + ")"
+	}
+	return "unsigned"
+}
+
+func bootOrderSummary(u system.UEFIBootOrder) string {
+	if len(u.Entries) == 0 {
+		return "unknown (run as administrator)"
+	}
+	var names []string
+	for _, e := range u.Entries {
+		if !e.Active {
+			continue
+		}
+		n := e.Description
+		if e.Current {
+			n += " (current)"
+		}
+		names = append(names, n)
+	}
+	return strings.Join(names, " > ")
+}
+
+func bcdSummary(b system.BCDInfo) string {
+	if b.Raw == "" && b.StartOptions == "" {
+		return "unknown (run as administrator)"
+	}
+	if b.Raw == "" {
+		// Start options only: bcdedit elements need elevation.
+		return fmt.Sprintf("testsigning=%v debug=%v nointegritychecks=%v (from start options)",
+			b.TestSigning, b.Debug, b.NoIntegrityChecks)
+	}
+	hv := b.HypervisorLaunchType
+	if hv == "" {
+		hv = "not set"
+	}
+	pol := b.BootMenuPolicy
+	if pol == "" {
+		pol = "not set"
+	}
+	return fmt.Sprintf("testsigning=%v debug=%v nointegritychecks=%v hypervisor=%s bootmenu=%s",
+		b.TestSigning, b.Debug, b.NoIntegrityChecks, hv, pol)
+}
+
+// sessionEnd formats the end of a log session, with the date when it ran past midnight.
+func sessionEnd(start, end time.Time) string {
+	if end.Format("2006-01-02") != start.Format("2006-01-02") {
+		return end.Format("2006-01-02 15:04")
+	}
+	return end.Format("15:04")
+}
+
+func vbsSummary(v system.VirtualizationInfo) string {
+	status := v.VBSStatus
+	if status == "" {
+		status = "unknown"
+	}
+	if len(v.SecurityServicesRunning) > 0 {
+		status += " (" + strings.Join(v.SecurityServicesRunning, ", ") + ")"
+	}
+	return status
+}
+
+// vbsWarnings names the Device Guard services that are actually on instead of a generic "VBS enabled".
+func vbsWarnings(v system.VirtualizationInfo) []string {
+	if !v.VBS_Enabled {
+		return nil
+	}
+
+	running := map[string]bool{}
+	var out []string
+	for _, svc := range v.SecurityServicesRunning {
+		running[svc] = true
+		switch svc {
+		case system.SecurityServiceHVCI:
+			out = append(out, "• Memory integrity (HVCI) is on: turn it off in Windows Security > Device security > Core isolation if Vanguard reports incompatible drivers")
+		case system.SecurityServiceCredentialGuard:
+			out = append(out, "• Credential Guard is running: it keeps the hypervisor loaded (disable it via Group Policy > Device Guard)")
+		case system.SecurityServiceSystemGuard:
+			out = append(out, "• System Guard Secure Launch is running: it keeps the hypervisor loaded")
+		default:
+			out = append(out, "• VBS service running: "+svc)
+		}
+	}
+	for _, svc := range v.SecurityServicesConfigured {
+		if !running[svc] {
+			out = append(out, "• "+svc+" is configured but not running (restart pending or unsupported hardware)")
+		}
+	}
+	if v.CodeIntegrityPolicy == "Enforced" || v.UserModeCodeIntegrityPolicy == "Enforced" {
+		out = append(out, fmt.Sprintf("• Code integrity policy enforced (kernel=%s, user=%s)", v.CodeIntegrityPolicy, v.UserModeCodeIntegrityPolicy))
+	}
+	if len(out) == 0 {
+		out = append(out, "• VBS "+strings.ToLower(v.VBSStatus)+" without security services (enabled by policy or a hypervisor feature)")
+	}
+	return out
+}
+
 func boxStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
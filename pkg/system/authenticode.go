@@ -0,0 +1,499 @@
+package system
+
+// Authenticode (PE signature) parser. The certificate table of a PE file holds
+// a PKCS#7 SignedData whose content is a SpcIndirectDataContent with the hash
+// of the image; the signer signs that content through the authenticated
+// attributes. We check both links here; trust (roots, db/dbx) is up to the caller.
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"debug/pe"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	winCertTypePKCS7 = 0x0002
+	peDirSecurity    = 4
+)
+
+var (
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidCounterSig    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 6}
+	oidRFC3161       = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 3, 3, 1}
+	oidNestedSig     = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 4, 1}
+	oidSHA1          = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256        = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384        = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512        = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+)
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional,tag:0"` // [0] EXPLICIT, unwrapped by hand
+}
+
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      pkcs7ContentInfo
+	Certificates     asn1.RawValue     `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue     `asn1:"optional,tag:1"`
+	SignerInfos      []pkcs7SignerInfo `asn1:"set"`
+}
+
+type pkcs7SignerInfo struct {
+	Version                   int
+	IssuerAndSerial           pkcs7IssuerAndSerial
+	DigestAlgorithm           pkix.AlgorithmIdentifier
+	AuthenticatedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedDigest           []byte
+	UnauthenticatedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type pkcs7IssuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type pkcs7Attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// tstInfo is the start of an RFC 3161 TSTInfo; later fields are ignored.
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint asn1.RawValue
+	Serial         *big.Int
+	GenTime        time.Time `asn1:"generalized"`
+}
+
+type spcIndirectDataContent struct {
+	Data          asn1.RawValue
+	MessageDigest spcDigestInfo
+}
+
+type spcDigestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+// peLayout holds the file offsets the Authenticode hash has to skip.
+type peLayout struct {
+	machine       uint16
+	checksumOff   int
+	secDirOff     int
+	sizeOfHeaders int
+	certOff       int
+	certSize      int
+	sections      []*pe.Section
+}
+
+// ReadPESignature reads a PE file and checks its embedded signature.
+func ReadPESignature(path string) (PESignature, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PESignature{}, err
+	}
+	return ParsePESignature(data)
+}
+
+// ParsePESignature parses the Authenticode signature of a PE image. An
+// unsigned image is not an error; a malformed one is.
+func ParsePESignature(data []byte) (PESignature, error) {
+	l, err := parsePELayout(data)
+	if err != nil {
+		return PESignature{}, err
+	}
+	sig := PESignature{Arch: PEMachineName(l.machine)}
+	sig.ImageSHA256 = hex.EncodeToString(peImageHash(data, l, crypto.SHA256))
+
+	if l.certSize == 0 {
+		return sig, nil
+	}
+	sig.Signed = true
+
+	blob, err := firstPKCS7Cert(data[l.certOff : l.certOff+l.certSize])
+	if err != nil {
+		sig.Error = err.Error()
+		return sig, nil
+	}
+	if err := verifyAuthenticode(blob, data, l, &sig); err != nil {
+		sig.Error = err.Error()
+	}
+	return sig, nil
+}
+
+func parsePELayout(data []byte) (peLayout, error) {
+	f, err := pe.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return peLayout{}, err
+	}
+	defer f.Close()
+
+	l := peLayout{machine: f.Machine, sections: f.Sections}
+	optOff := int(binary.LittleEndian.Uint32(data[0x3c:])) + 4 + 20
+	l.checksumOff = optOff + 64
+
+	var dirs [16]pe.DataDirectory
+	var ndirs uint32
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		l.secDirOff = optOff + 96 + peDirSecurity*8
+		l.sizeOfHeaders = int(oh.SizeOfHeaders)
+		dirs, ndirs = oh.DataDirectory, oh.NumberOfRvaAndSizes
+	case *pe.OptionalHeader64:
+		l.secDirOff = optOff + 112 + peDirSecurity*8
+		l.sizeOfHeaders = int(oh.SizeOfHeaders)
+		dirs, ndirs = oh.DataDirectory, oh.NumberOfRvaAndSizes
+	default:
+		return peLayout{}, errors.New("PE file without optional header")
+	}
+	if l.secDirOff+8 > len(data) || l.sizeOfHeaders > len(data) {
+		return peLayout{}, errors.New("truncated PE header")
+	}
+	// peImageHash slices the headers at these offsets, in this order.
+	if l.checksumOff+4 > l.secDirOff || l.sizeOfHeaders < l.secDirOff+8 {
+		return peLayout{}, errors.New("SizeOfHeaders does not cover the optional header")
+	}
+
+	if ndirs > peDirSecurity {
+		// The security directory holds a file offset, not an RVA.
+		l.certOff = int(dirs[peDirSecurity].VirtualAddress)
+		l.certSize = int(dirs[peDirSecurity].Size)
+		if l.certSize > 0 && (l.certOff < l.sizeOfHeaders || l.certOff+l.certSize > len(data)) {
+			return peLayout{}, errors.New("certificate table outside the file")
+		}
+	}
+	return l, nil
+}
+
+// peImageHash computes the Authenticode image hash: headers without the
+// checksum and the security directory entry, sections in file order, then any
+// trailing data except the certificate table.
+func peImageHash(data []byte, l peLayout, alg crypto.Hash) []byte {
+	h := alg.New()
+	h.Write(data[:l.checksumOff])
+	h.Write(data[l.checksumOff+4 : l.secDirOff])
+	h.Write(data[l.secDirOff+8 : l.sizeOfHeaders])
+
+	secs := make([]*pe.Section, 0, len(l.sections))
+	for _, s := range l.sections {
+		if s.Size > 0 {
+			secs = append(secs, s)
+		}
+	}
+	sort.Slice(secs, func(i, j int) bool { return secs[i].Offset < secs[j].Offset })
+
+	end := l.sizeOfHeaders
+	for _, s := range secs {
+		lo, hi := int(s.Offset), int(s.Offset)+int(s.Size)
+		if lo < 0 || hi > len(data) {
+			continue
+		}
+		h.Write(data[lo:hi])
+		end = max(end, hi)
+	}
+
+	if end < len(data) {
+		tail := data[end:]
+		if l.certSize > 0 && l.certOff >= end {
+			tail = append(append([]byte{}, data[end:l.certOff]...), data[l.certOff+l.certSize:]...)
+		}
+		h.Write(tail)
+	}
+	return h.Sum(nil)
+}
+
+// firstPKCS7Cert returns the PKCS#7 blob of the first WIN_CERTIFICATE entry.
+func firstPKCS7Cert(table []byte) ([]byte, error) {
+	if len(table) < 8 {
+		return nil, errors.New("certificate table too short")
+	}
+	n := int(binary.LittleEndian.Uint32(table))
+	typ := binary.LittleEndian.Uint16(table[6:])
+	if n < 8 || n > len(table) {
+		return nil, errors.New("bad WIN_CERTIFICATE length")
+	}
+	if typ != winCertTypePKCS7 {
+		return nil, errors.New("certificate is not PKCS#7 signed data")
+	}
+	return table[8:n], nil
+}
+
+func verifyAuthenticode(blob, data []byte, l peLayout, sig *PESignature) error {
+	var ci pkcs7ContentInfo
+	if _, err := asn1.Unmarshal(blob, &ci); err != nil {
+		return err
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return errors.New("not a PKCS#7 SignedData")
+	}
+	var sd pkcs7SignedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return err
+	}
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return err
+	}
+	if len(sd.SignerInfos) == 0 {
+		return errors.New("no signer")
+	}
+	si := sd.SignerInfos[0]
+
+	var signer *x509.Certificate
+	for _, c := range certs {
+		if c.SerialNumber.Cmp(si.IssuerAndSerial.Serial) == 0 && bytes.Equal(c.RawIssuer, si.IssuerAndSerial.Issuer.FullBytes) {
+			signer = c
+			break
+		}
+	}
+	if signer == nil {
+		return errors.New("signer certificate not included")
+	}
+	sig.Certs = append([]*x509.Certificate{signer}, without(certs, signer)...)
+	sig.Signer = signer.Subject.CommonName
+	sig.SignerOrg = firstString(signer.Subject.Organization)
+	sig.Issuer = signer.Issuer.CommonName
+
+	// Timestamp and nested (dual) signatures live in the unauthenticated attributes.
+	for _, a := range attributeSet(si.UnauthenticatedAttributes) {
+		switch {
+		case a.Type.Equal(oidCounterSig) || a.Type.Equal(oidRFC3161):
+			if t := timestampTime(a); !t.IsZero() {
+				sig.SigningTime = t
+			}
+		case a.Type.Equal(oidNestedSig):
+			rest := a.Values.Bytes
+			for len(rest) > 0 {
+				var nb asn1.RawValue
+				var err error
+				if rest, err = asn1.Unmarshal(rest, &nb); err != nil {
+					break
+				}
+				n := PESignature{Arch: sig.Arch, Signed: true, ImageSHA256: sig.ImageSHA256}
+				if err := verifyAuthenticode(nb.FullBytes, data, l, &n); err != nil {
+					n.Error = err.Error()
+				}
+				sig.Nested = append(sig.Nested, n)
+			}
+		}
+	}
+
+	// 1. Image hash vs. the hash in SpcIndirectDataContent.
+	var idc spcIndirectDataContent
+	if _, err := asn1.Unmarshal(sd.ContentInfo.Content.Bytes, &idc); err != nil {
+		return err
+	}
+	alg, name := digestAlg(idc.MessageDigest.Algorithm.Algorithm)
+	if alg == 0 {
+		return errors.New("unsupported digest algorithm")
+	}
+	sig.DigestAlg = name
+	if !bytes.Equal(peImageHash(data, l, alg), idc.MessageDigest.Digest) {
+		return errors.New("image hash mismatch (file modified after signing)")
+	}
+
+	// 2. Signer signature over the content (through the authenticated attributes).
+	salg, _ := digestAlg(si.DigestAlgorithm.Algorithm)
+	if salg == 0 {
+		return errors.New("unsupported signer digest algorithm")
+	}
+	var seq asn1.RawValue
+	if _, err := asn1.Unmarshal(sd.ContentInfo.Content.Bytes, &seq); err != nil {
+		return err
+	}
+	content := seq.Bytes // SEQUENCE contents, without tag and length
+	signed := content
+	if len(si.AuthenticatedAttributes.FullBytes) > 0 {
+		attrs := append([]byte{}, si.AuthenticatedAttributes.FullBytes...)
+		attrs[0] = 0x31 // signed as a SET OF, stored as [0] IMPLICIT
+		md, err := attrMessageDigest(attrs)
+		if err != nil {
+			return err
+		}
+		h := salg.New()
+		h.Write(content)
+		if !bytes.Equal(h.Sum(nil), md) {
+			return errors.New("content digest mismatch")
+		}
+		signed = attrs
+	}
+	h := salg.New()
+	h.Write(signed)
+	if err := checkSignerSignature(signer, salg, h.Sum(nil), si.EncryptedDigest); err != nil {
+		return err
+	}
+	sig.Valid = true
+	return nil
+}
+
+// VerifySignerChain checks that the signing certificate chains to roots
+// (nil: the system store) for code signing, at the timestamp if there is one.
+func VerifySignerChain(sig PESignature, roots *x509.CertPool) error {
+	if len(sig.Certs) == 0 {
+		return errors.New("no signer certificate")
+	}
+	inter := x509.NewCertPool()
+	for _, c := range sig.Certs[1:] {
+		inter.AddCert(c)
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: inter,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		CurrentTime:   sig.SigningTime,
+	}
+	_, err := sig.Certs[0].Verify(opts)
+	return err
+}
+
+// attributeSet decodes [0]/[1] IMPLICIT SET OF Attribute.
+func attributeSet(raw asn1.RawValue) []pkcs7Attribute {
+	if len(raw.FullBytes) == 0 {
+		return nil
+	}
+	set := append([]byte{}, raw.FullBytes...)
+	set[0] = 0x31
+	var attrs []pkcs7Attribute
+	if _, err := asn1.UnmarshalWithParams(set, &attrs, "set"); err != nil {
+		return nil
+	}
+	return attrs
+}
+
+// timestampTime reads the time of a legacy counter-signature or an RFC 3161
+// timestamp token.
+func timestampTime(a pkcs7Attribute) time.Time {
+	if a.Type.Equal(oidCounterSig) {
+		var cs pkcs7SignerInfo
+		if _, err := asn1.Unmarshal(a.Values.Bytes, &cs); err != nil {
+			return time.Time{}
+		}
+		for _, ca := range attributeSet(cs.AuthenticatedAttributes) {
+			var t time.Time
+			if ca.Type.Equal(oidSigningTime) {
+				if _, err := asn1.Unmarshal(ca.Values.Bytes, &t); err == nil {
+					return t
+				}
+			}
+		}
+		return time.Time{}
+	}
+
+	var ci pkcs7ContentInfo
+	var sd pkcs7SignedData
+	var octets []byte
+	var tst tstInfo
+	if _, err := asn1.Unmarshal(a.Values.Bytes, &ci); err != nil {
+		return time.Time{}
+	}
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return time.Time{}
+	}
+	if _, err := asn1.Unmarshal(sd.ContentInfo.Content.Bytes, &octets); err != nil {
+		return time.Time{}
+	}
+	if _, err := asn1.Unmarshal(octets, &tst); err != nil {
+		return time.Time{}
+	}
+	return tst.GenTime
+}
+
+func attrMessageDigest(set []byte) ([]byte, error) {
+	var attrs []pkcs7Attribute
+	if _, err := asn1.UnmarshalWithParams(set, &attrs, "set"); err != nil {
+		return nil, err
+	}
+	for _, a := range attrs {
+		if a.Type.Equal(oidMessageDigest) {
+			var md []byte
+			if _, err := asn1.Unmarshal(a.Values.Bytes, &md); err != nil {
+				return nil, err
+			}
+			return md, nil
+		}
+	}
+	return nil, errors.New("no messageDigest attribute")
+}
+
+func checkSignerSignature(c *x509.Certificate, alg crypto.Hash, digest, sig []byte) error {
+	switch pub := c.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, alg, digest, sig)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest, sig) {
+			return errors.New("ecdsa signature invalid")
+		}
+		return nil
+	}
+	return errors.New("unsupported signer key type")
+}
+
+func digestAlg(oid asn1.ObjectIdentifier) (crypto.Hash, string) {
+	switch {
+	case oid.Equal(oidSHA1):
+		return crypto.SHA1, "sha1"
+	case oid.Equal(oidSHA256):
+		return crypto.SHA256, "sha256"
+	case oid.Equal(oidSHA384):
+		return crypto.SHA384, "sha384"
+	case oid.Equal(oidSHA512):
+		return crypto.SHA512, "sha512"
+	}
+	return 0, ""
+}
+
+// PEMachineName maps IMAGE_FILE_MACHINE_* to the names used in EFI file names.
+func PEMachineName(m uint16) string {
+	switch m {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "x64"
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "ia32"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "aa64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT:
+		return "arm"
+	case pe.IMAGE_FILE_MACHINE_RISCV64:
+		return "riscv64"
+	}
+	return "unknown"
+}
+
+func without(certs []*x509.Certificate, c *x509.Certificate) []*x509.Certificate {
+	var out []*x509.Certificate
+	for _, x := range certs {
+		if x != c {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func firstString(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return s[0]
+}
@@ -0,0 +1,97 @@
+package system
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testdata/pe/signed.exe is golang.org/x/sys/windows/testdata/ev-signed-file.exe:
+// a 32-bit console program signed with an EV certificate and an RFC 3161 timestamp.
+func readPEFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", "pe", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// optionalHeaderOffset returns the file offset of the optional header.
+func optionalHeaderOffset(data []byte) int {
+	return int(binary.LittleEndian.Uint32(data[0x3c:])) + 4 + 20
+}
+
+func TestParsePESignature(t *testing.T) {
+	sig, err := ParsePESignature(readPEFixture(t, "signed.exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Signed || !sig.Valid || sig.Error != "" {
+		t.Fatalf("signature not valid: %+v", sig)
+	}
+	if sig.SignerOrg != "WireGuard LLC" || !strings.HasPrefix(sig.Issuer, "DigiCert") || sig.DigestAlg != "sha256" {
+		t.Errorf("signer: %+v", sig)
+	}
+	if sig.Arch != "ia32" || sig.SigningTime.Year() != 2021 {
+		t.Errorf("arch %q, signing time %v", sig.Arch, sig.SigningTime)
+	}
+}
+
+func TestParsePESignatureTampered(t *testing.T) {
+	data := readPEFixture(t, "signed.exe")
+	data[0x400] ^= 0xFF // first byte of .text
+	sig, err := ParsePESignature(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Signed || sig.Valid || !strings.Contains(sig.Error, "hash") {
+		t.Fatalf("tampered image accepted: %+v", sig)
+	}
+}
+
+func TestParsePESignatureMalformedHeader(t *testing.T) {
+	for name, corrupt := range map[string]func([]byte){
+		// SizeOfHeaders (optional header +60) smaller than the security directory.
+		"small SizeOfHeaders": func(b []byte) {
+			binary.LittleEndian.PutUint32(b[optionalHeaderOffset(b)+60:], 0x40)
+		},
+		"SizeOfHeaders past EOF": func(b []byte) {
+			binary.LittleEndian.PutUint32(b[optionalHeaderOffset(b)+60:], 0x7FFFFFFF)
+		},
+		"certificate table past EOF": func(b []byte) {
+			binary.LittleEndian.PutUint32(b[optionalHeaderOffset(b)+96+peDirSecurity*8+4:], 0x7FFFFFFF)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := readPEFixture(t, "signed.exe")
+			corrupt(data)
+			if _, err := ParsePESignature(data); err == nil {
+				t.Fatal("malformed header accepted")
+			}
+		})
+	}
+}
+
+func TestScanESPMalformedLoader(t *testing.T) {
+	root := t.TempDir()
+	boot := filepath.Join(root, "EFI", "Boot")
+	if err := os.MkdirAll(boot, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := readPEFixture(t, "signed.exe")
+	binary.LittleEndian.PutUint32(data[optionalHeaderOffset(data)+60:], 0x40)
+	if err := os.WriteFile(filepath.Join(boot, "bootx64.efi"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	inv, err := ScanESP(root, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inv.Binaries) != 1 || inv.Binaries[0].SecureBoot != SBVerdictInvalid {
+		t.Fatalf("malformed loader: %+v", inv.Binaries)
+	}
+}
@@ -0,0 +1,72 @@
+package system
+
+// Parsers for the boot configuration of the running system:
+//   - `bcdedit /enum {current}`: element names (testsigning, debug, ...) are
+//     never translated, the Yes/No values are. Only known "yes" words count as
+//     enabled; anything else is treated as unknown and does not block. The
+//     output is in the console code page: decode it with DecodeConsoleOutput.
+//   - HKLM\SYSTEM\CurrentControlSet\Control\SystemStartOptions: the options the
+//     kernel was started with ("TESTSIGNING  DEBUG  DISABLE_INTEGRITY_CHECKS"),
+//     the same in every UI language and readable without elevation.
+
+import "strings"
+
+var bcdYes = []string{
+	"yes", "ja", "oui", "sí", "si", "sì", "sim", "tak", "ano", "áno", "igen", "kyllä", "evet",
+	"da", "jah", "jā", "taip", "ναι", "да", "так", "是", "はい", "예", "כן", "نعم", "ใช่", "có", "ya",
+}
+
+func ParseBCDEdit(out string) BCDInfo {
+	bi := BCDInfo{Raw: out}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(strings.TrimRight(line, "\r"))
+		if len(fields) < 2 {
+			continue
+		}
+		key := strings.ToLower(fields[0])
+		val := strings.Join(fields[1:], " ")
+
+		switch key {
+		case "testsigning":
+			bi.TestSigning = bcdBool(val)
+		case "debug":
+			bi.Debug = bcdBool(val)
+		case "nointegritychecks":
+			bi.NoIntegrityChecks = bcdBool(val)
+		case "hypervisorlaunchtype":
+			bi.HypervisorLaunchType = val
+		case "bootmenupolicy":
+			bi.BootMenuPolicy = val
+		}
+	}
+	return bi
+}
+
+// ParseSystemStartOptions reads the kernel start options into bi, keeping
+// what bcdedit already reported (a bcdedit change applies after a restart).
+func ParseSystemStartOptions(opts string, bi BCDInfo) BCDInfo {
+	bi.StartOptions = strings.TrimSpace(opts)
+	for _, o := range strings.Fields(strings.ToUpper(opts)) {
+		name, _, _ := strings.Cut(o, "=")
+		switch name {
+		case "TESTSIGNING":
+			bi.TestSigning = true
+		case "DEBUG":
+			bi.Debug = true
+		case "DISABLE_INTEGRITY_CHECKS":
+			bi.NoIntegrityChecks = true
+		}
+	}
+	return bi
+}
+
+func bcdBool(v string) bool {
+	v = strings.ToLower(strings.TrimSpace(v))
+	for _, yes := range bcdYes {
+		if v == yes {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBCDEditLocales(t *testing.T) {
+	for _, tc := range []struct {
+		lang                          string
+		cp                            uint32 // code page bcdedit wrote the fixture in
+		testSigning, debug, noIntegCk bool
+	}{
+		{"en", 437, true, false, false},
+		{"de", 850, true, false, false},
+		{"fr", 850, false, true, false},
+		{"ja", 932, false, true, false},
+		{"unknown", 437, false, false, false},
+	} {
+		t.Run(tc.lang, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join("testdata", "bcdedit", "enum_"+tc.lang+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			bi := ParseBCDEdit(DecodeConsoleOutput(b, tc.cp))
+			if bi.TestSigning != tc.testSigning || bi.Debug != tc.debug || bi.NoIntegrityChecks != tc.noIntegCk {
+				t.Errorf("testsigning=%v debug=%v nointegritychecks=%v, want %v %v %v",
+					bi.TestSigning, bi.Debug, bi.NoIntegrityChecks, tc.testSigning, tc.debug, tc.noIntegCk)
+			}
+			if bi.HypervisorLaunchType != "Off" || bi.BootMenuPolicy != "Standard" {
+				t.Errorf("hypervisorlaunchtype=%q bootmenupolicy=%q", bi.HypervisorLaunchType, bi.BootMenuPolicy)
+			}
+		})
+	}
+}
+
+func TestParseSystemStartOptions(t *testing.T) {
+	bi := ParseSystemStartOptions(" NOEXECUTE=OPTIN  FVEBOOT=2641920  NOVGA", BCDInfo{})
+	if bi.TestSigning || bi.Debug || bi.NoIntegrityChecks {
+		t.Errorf("clean boot: %+v", bi)
+	}
+
+	bi = ParseSystemStartOptions(" TESTSIGNING  NOEXECUTE=OPTIN  DEBUG  DEBUGPORT=NET  DISABLE_INTEGRITY_CHECKS", BCDInfo{})
+	if !bi.TestSigning || !bi.Debug || !bi.NoIntegrityChecks {
+		t.Errorf("test boot: %+v", bi)
+	}
+
+	// bcdedit already reports a pending change that the running kernel does not have yet.
+	bi = ParseSystemStartOptions(" NOEXECUTE=OPTIN", BCDInfo{TestSigning: true, HypervisorLaunchType: "Auto"})
+	if !bi.TestSigning || bi.HypervisorLaunchType != "Auto" || bi.StartOptions != "NOEXECUTE=OPTIN" {
+		t.Errorf("merge: %+v", bi)
+	}
+}
+
+func TestDecodeConsoleOutput(t *testing.T) {
+	for _, tc := range []struct {
+		in   []byte
+		cp   uint32
+		want string
+	}{
+		{[]byte{0x82, 0xcd, 0x82, 0xa2}, 932, "はい"},
+		{[]byte("d\x82marrage"), 850, "démarrage"},
+		{[]byte{0xa4, 0xa0}, 866, "да"},
+		{[]byte("Yes"), 437, "Yes"},
+		{[]byte("はい"), 65001, "はい"},
+		{[]byte("Ja"), 0, "Ja"}, // unknown code page: unchanged
+	} {
+		if got := DecodeConsoleOutput(tc.in, tc.cp); got != tc.want {
+			t.Errorf("DecodeConsoleOutput(% x, %d) = %q, want %q", tc.in, tc.cp, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package system
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// GetBCDInfo reads the boot entry in use. The kernel start options in the
+// registry give test signing / debug / integrity checks without elevation;
+// bcdedit adds the pending values and the other elements but needs an
+// elevated prompt.
+func GetBCDInfo() (BCDInfo, error) {
+	var bi BCDInfo
+	var errs []error
+
+	out, err := exec.Command("bcdedit", "/enum", "{current}").CombinedOutput()
+	s := strings.TrimSpace(DecodeConsoleOutput(out, consoleOutputCP()))
+	switch {
+	case err == nil:
+		bi = ParseBCDEdit(s)
+	case s == "":
+		errs = append(errs, err)
+	default:
+		errs = append(errs, errors.New("bcdedit failed (run as administrator): "+firstLine(s)))
+	}
+
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Control`, registry.QUERY_VALUE)
+	if err == nil {
+		var opts string
+		opts, _, err = k.GetStringValue("SystemStartOptions")
+		k.Close()
+		if err == nil {
+			bi = ParseSystemStartOptions(opts, bi)
+		}
+	}
+	if err != nil {
+		errs = append(errs, errors.New("SystemStartOptions: "+err.Error()))
+	}
+
+	return bi, errors.Join(errs...)
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package system
+
+// BitLocker state of the OS volume. Firmware changes (CSM/UEFI, Secure Boot,
+// clearing the TPM) alter the PCRs the TPM protector is sealed to, so the next
+// boot asks for the recovery key.
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Lines of manage-bde -protectors -get that list the PCR validation profile
+// ("7, 11"); the labels around them are localized.
+var rePCRList = regexp.MustCompile(`^\s*\d{1,2}(\s*,\s*\d{1,2})*\s*$`)
+
+// ParsePCRProfile returns the PCRs of the TPM protector from
+// `manage-bde -protectors -get C:` output. Only the lines indented below the
+// TPM protector header ("TPM:", "TPM And PIN:", "TPM :") are read; other
+// protectors can print digit-only lines too.
+func ParsePCRProfile(out string) []int {
+	var pcrs []int
+	inTPM, indent := false, 0
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		ind := len(line) - len(strings.TrimLeft(line, " \t"))
+		if inTPM && ind <= indent {
+			inTPM = false // next protector or section
+		}
+		if !inTPM {
+			if strings.HasPrefix(trimmed, "TPM") && strings.HasSuffix(trimmed, ":") {
+				inTPM, indent = true, ind
+			}
+			continue
+		}
+		if !rePCRList.MatchString(line) {
+			continue
+		}
+		for _, f := range strings.Split(line, ",") {
+			if n, err := strconv.Atoi(strings.TrimSpace(f)); err == nil && n < 24 {
+				pcrs = append(pcrs, n)
+			}
+		}
+	}
+	return pcrs
+}
+
+// Encrypted reports whether the OS volume is (partly) encrypted; the recovery
+// key is needed whenever protection gets triggered.
+func (b BitLockerInfo) Encrypted() bool {
+	return b.Read && b.VolumeStatus != "" && b.VolumeStatus != "FullyDecrypted"
+}
+
+// HasTPMProtector reports whether one of the protectors is sealed to the TPM.
+func (b BitLockerInfo) HasTPMProtector() bool {
+	for _, p := range b.Protectors {
+		if strings.HasPrefix(p, "Tpm") {
+			return true
+		}
+	}
+	return false
+}
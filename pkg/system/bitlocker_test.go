@@ -0,0 +1,47 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParsePCRProfile(t *testing.T) {
+	for lang, want := range map[string][]int{
+		"en": {7, 11},
+		"de": {0, 2, 4, 11},
+		"fr": {7, 11},
+	} {
+		t.Run(lang, func(t *testing.T) {
+			out, err := os.ReadFile(filepath.Join("testdata", "bitlocker", "protectors_"+lang+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ParsePCRProfile(string(out)); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// Digit-only lines outside the TPM protector block are not PCRs.
+func TestParsePCRProfileOtherProtectors(t *testing.T) {
+	out := "Volume C: [Windows]\n" +
+		"All Key Protectors\n\n" +
+		"    Password:\n" +
+		"      ID: {00000000-0000-0000-0000-000000000000}\n" +
+		"        12\n\n" +
+		"    TPM:\n" +
+		"      ID: {11111111-1111-1111-1111-111111111111}\n" +
+		"      PCR Validation Profile:\n" +
+		"        7\n" +
+		"    External Key:\n" +
+		"        3, 4\n"
+	if got := ParsePCRProfile(out); !reflect.DeepEqual(got, []int{7}) {
+		t.Errorf("got %v, want [7]", got)
+	}
+	if got := ParsePCRProfile("    Numerical Password:\n        7, 11\n"); got != nil {
+		t.Errorf("no TPM protector: got %v", got)
+	}
+}
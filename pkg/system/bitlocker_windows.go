@@ -0,0 +1,63 @@
+//go:build windows
+
+package system
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+)
+
+type psBitLocker struct {
+	MountPoint           string   `json:"MountPoint"`
+	VolumeStatus         string   `json:"VolumeStatus"`
+	ProtectionStatus     string   `json:"ProtectionStatus"`
+	EncryptionPercentage float64  `json:"EncryptionPercentage"`
+	KeyProtector         []string `json:"KeyProtector"`
+}
+
+// GetBitLockerInfo reads the BitLocker state of the system drive. Both
+// Get-BitLockerVolume and manage-bde need an elevated prompt.
+func GetBitLockerInfo() (BitLockerInfo, error) {
+	drive := os.Getenv("SystemDrive")
+	if drive == "" {
+		drive = "C:"
+	}
+
+	script := strings.Join([]string{
+		"$ErrorActionPreference='Stop';",
+		"$v = Get-BitLockerVolume -MountPoint '" + drive + "';",
+		"[pscustomobject]@{ MountPoint = $v.MountPoint; VolumeStatus = [string]$v.VolumeStatus;",
+		"  ProtectionStatus = [string]$v.ProtectionStatus; EncryptionPercentage = $v.EncryptionPercentage;",
+		"  KeyProtector = @($v.KeyProtector | ForEach-Object { [string]$_.KeyProtectorType }) } | ConvertTo-Json -Compress",
+	}, " ")
+	out, stderr, err := runPS64(script)
+	if err != nil || len(out) == 0 {
+		if stderr != "" {
+			return BitLockerInfo{}, errors.New("Get-BitLockerVolume failed (run as administrator): " + firstLine(stderr))
+		}
+		return BitLockerInfo{}, err
+	}
+
+	var v psBitLocker
+	if err := json.Unmarshal(out, &v); err != nil {
+		return BitLockerInfo{}, err
+	}
+	bl := BitLockerInfo{
+		Read:                 true,
+		MountPoint:           v.MountPoint,
+		VolumeStatus:         v.VolumeStatus,
+		ProtectionOn:         v.ProtectionStatus == "On",
+		EncryptionPercentage: int(v.EncryptionPercentage),
+		Protectors:           v.KeyProtector,
+	}
+	bl.HasRecoveryPassword = slices.Contains(bl.Protectors, "RecoveryPassword")
+
+	if bl.HasTPMProtector() {
+		bl.PCRs = ParsePCRProfile(run("manage-bde", "-protectors", "-get", drive))
+		bl.PCR7Bound = slices.Contains(bl.PCRs, 7)
+	}
+	return bl, nil
+}
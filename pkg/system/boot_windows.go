@@ -12,14 +12,35 @@ import (
 )
 
 func GetBootInfo() (BootInfo, error) {
+	bi := BootInfo{BIOSMode: detectBIOSMode()}
+
+	// Firmware variables: fail with ERROR_INVALID_FUNCTION on a CSM boot.
+	if sbv, err := readEFIVar("SecureBoot", efiGlobalGUID); err == nil {
+		bi.FirmwareVarsRead = true
+		bi.SecureBootVar = len(sbv) > 0 && sbv[0] == 1
+		if sm, err := readEFIVar("SetupMode", efiGlobalGUID); err == nil {
+			bi.SetupMode = len(sm) > 0 && sm[0] == 1
+		}
+	} else if err == windows.ERROR_INVALID_FUNCTION {
+		bi.BootedViaCSM = true
+	}
+	if bi.BIOSMode == "Legacy" {
+		bi.BootedViaCSM = true
+	}
+
+	bi.FastStartup = fastStartupEnabled()
+	return bi, nil
+}
+
+func detectBIOSMode() string {
 	if k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Control`, registry.QUERY_VALUE); err == nil {
 		defer k.Close()
 		if v, _, e := k.GetIntegerValue("PEFirmwareType"); e == nil {
 			switch v {
 			case 2:
-				return BootInfo{BIOSMode: "UEFI"}, nil
+				return "UEFI"
 			case 1:
-				return BootInfo{BIOSMode: "Legacy"}, nil
+				return "Legacy"
 			}
 		}
 	}
@@ -27,12 +48,32 @@ func GetBootInfo() (BootInfo, error) {
 	out := runPS("(Get-ComputerInfo).BiosFirmwareType")
 	up := strings.ToUpper(strings.TrimSpace(out))
 	if strings.Contains(up, "UEFI") {
-		return BootInfo{BIOSMode: "UEFI"}, nil
+		return "UEFI"
 	}
 	if strings.Contains(up, "LEGACY") || strings.Contains(up, "BIOS") {
-		return BootInfo{BIOSMode: "Legacy"}, nil
+		return "Legacy"
+	}
+	return "Unknown"
+}
+
+// fastStartupEnabled: HiberbootEnabled=1 and hibernation not turned off.
+func fastStartupEnabled() bool {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Control\Session Manager\Power`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer k.Close()
+	if v, _, e := k.GetIntegerValue("HiberbootEnabled"); e != nil || v != 1 {
+		return false
+	}
+
+	if p, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Control\Power`, registry.QUERY_VALUE); err == nil {
+		defer p.Close()
+		if v, _, e := p.GetIntegerValue("HibernateEnabled"); e == nil && v == 0 {
+			return false
+		}
 	}
-	return BootInfo{BIOSMode: "Unknown"}, nil
+	return true
 }
 
 func GetSecureBootKeys(sb SecureBoot) (SecureBootKeys, error) {
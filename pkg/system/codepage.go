@@ -0,0 +1,56 @@
+package system
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// oemCodePages are the console (OEM) code pages Windows ships with.
+var oemCodePages = map[uint32]encoding.Encoding{
+	437: charmap.CodePage437,
+	850: charmap.CodePage850,
+	852: charmap.CodePage852,
+	855: charmap.CodePage855,
+	858: charmap.CodePage858,
+	860: charmap.CodePage860,
+	862: charmap.CodePage862,
+	863: charmap.CodePage863,
+	865: charmap.CodePage865,
+	866: charmap.CodePage866,
+	874: charmap.Windows874,
+	932: japanese.ShiftJIS,
+	936: simplifiedchinese.GBK,
+	949: korean.EUCKR,
+	950: traditionalchinese.Big5,
+
+	1250: charmap.Windows1250,
+	1251: charmap.Windows1251,
+	1252: charmap.Windows1252,
+	1253: charmap.Windows1253,
+	1254: charmap.Windows1254,
+	1255: charmap.Windows1255,
+	1256: charmap.Windows1256,
+	1257: charmap.Windows1257,
+	1258: charmap.Windows1258,
+}
+
+// DecodeConsoleOutput converts what a console program wrote to a pipe, in
+// code page cp, to UTF-8. UTF-8 (65001) and unknown code pages are returned
+// as they are.
+func DecodeConsoleOutput(b []byte, cp uint32) string {
+	enc, ok := oemCodePages[cp]
+	if !ok {
+		return string(b)
+	}
+	out, err := enc.NewDecoder().Bytes(b)
+	if err != nil || !utf8.Valid(out) {
+		return string(b)
+	}
+	return string(out)
+}
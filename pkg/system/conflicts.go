@@ -0,0 +1,63 @@
+package system
+
+// Other anti-cheats and third-party antivirus products known to interfere
+// with vgc/vgk. Product definitions live in conflicts.json.
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+)
+
+//go:embed conflicts.json
+var conflictDefinitionsJSON []byte
+
+type ConflictDefinition struct {
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`      // "anticheat" / "antivirus"
+	Services  []string `json:"services"`  // service/driver names
+	Antivirus []string `json:"antivirus"` // substrings of SecurityCenter2 displayName
+	Note      string   `json:"note"`
+}
+
+func ConflictDefinitions() ([]ConflictDefinition, error) {
+	var defs []ConflictDefinition
+	err := json.Unmarshal(conflictDefinitionsJSON, &defs)
+	return defs, err
+}
+
+// MatchConflicts applies defs to the installed services (looked up via service)
+// and the registered antivirus products.
+func MatchConflicts(defs []ConflictDefinition, service func(name string) (exists, running bool), av []AntivirusProduct) []ConflictProduct {
+	var out []ConflictProduct
+	for _, d := range defs {
+		for _, name := range d.Services {
+			if exists, running := service(name); exists {
+				out = append(out, ConflictProduct{Name: d.Name, Kind: d.Kind, Source: name, Running: running, Note: d.Note})
+				break
+			}
+		}
+		for _, p := range av {
+			if matchesAny(p.Name, d.Antivirus) {
+				out = append(out, ConflictProduct{Name: d.Name, Kind: d.Kind, Source: p.Name, Running: p.Enabled, Note: d.Note})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// AntivirusEnabled decodes the real-time protection bit of SecurityCenter2 productState.
+func AntivirusEnabled(productState uint32) bool {
+	return (productState>>12)&0xF == 1
+}
+
+func matchesAny(s string, subs []string) bool {
+	low := strings.ToLower(s)
+	for _, x := range subs {
+		if x != "" && strings.Contains(low, strings.ToLower(x)) {
+			return true
+		}
+	}
+	return false
+}
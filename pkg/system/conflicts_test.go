@@ -0,0 +1,73 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchConflicts(t *testing.T) {
+	defs, err := ConflictDefinitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	services := map[string]bool{ // name -> running
+		"FACEIT":        true,
+		"FACEITService": true,
+		"BEService":     false,
+		"WinDefend":     true,
+	}
+	service := func(name string) (bool, bool) {
+		running, ok := services[name]
+		return ok, running
+	}
+	av := []AntivirusProduct{
+		{Name: "Windows Defender", Enabled: true},
+		{Name: "Avast Free Antivirus", Enabled: true},
+		{Name: "AVG Internet Security", Enabled: false},
+	}
+
+	type match struct {
+		Name, Kind, Source string
+		Running            bool
+	}
+	var got []match
+	for _, c := range MatchConflicts(defs, service, av) {
+		if c.Note == "" {
+			t.Errorf("%s: no note", c.Name)
+		}
+		got = append(got, match{c.Name, c.Kind, c.Source, c.Running})
+	}
+	want := []match{
+		{"FACEIT Anti-Cheat", "anticheat", "FACEIT", true}, // one entry per product
+		{"BattlEye", "anticheat", "BEService", false},
+		{"Avast", "antivirus", "Avast Free Antivirus", true},
+		{"AVG", "antivirus", "AVG Internet Security", false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	none := func(string) (bool, bool) { return false, false }
+	if c := MatchConflicts(defs, none, []AntivirusProduct{{Name: "Windows Defender", Enabled: true}}); c != nil {
+		t.Errorf("Defender only: %+v", c)
+	}
+}
+
+// productState packs the product type, the real-time protection state
+// (bits 12-15: 0 off, 1 on, 2 snoozed, 3 expired) and the signature state.
+func TestAntivirusEnabled(t *testing.T) {
+	for state, want := range map[uint32]bool{
+		397568:   true,  // 0x061100 Defender on, up to date
+		397584:   true,  // 0x061110 on, signatures out of date
+		393472:   false, // 0x060100 Defender off
+		266240:   true,  // 0x041000 third-party on
+		262144:   false, // 0x040000 third-party off
+		0x062100: false, // snoozed
+		0x063100: false, // expired
+		0:        false,
+	} {
+		if got := AntivirusEnabled(state); got != want {
+			t.Errorf("AntivirusEnabled(0x%06X) = %v, want %v", state, got, want)
+		}
+	}
+}
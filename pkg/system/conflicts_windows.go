@@ -0,0 +1,42 @@
+//go:build windows
+
+package system
+
+import (
+	"github.com/StackExchange/wmi"
+)
+
+type antiVirusProduct struct {
+	DisplayName  string
+	ProductState uint32
+}
+
+// GetConflictInfo looks for other anti-cheats and antivirus products known to interfere with Vanguard.
+func GetConflictInfo() (ConflictInfo, error) {
+	var ci ConflictInfo
+
+	defs, err := ConflictDefinitions()
+	if err != nil {
+		return ci, err
+	}
+
+	// SecurityCenter2 only exists on client editions; a failure here is not fatal.
+	var avs []antiVirusProduct
+	if e := wmi.QueryNamespace("SELECT displayName, productState FROM AntiVirusProduct", &avs, `root\SecurityCenter2`); e == nil {
+		for _, a := range avs {
+			ci.Antivirus = append(ci.Antivirus, AntivirusProduct{Name: cleanWS(a.DisplayName), Enabled: AntivirusEnabled(a.ProductState)})
+		}
+	} else {
+		err = e
+	}
+
+	ci.Conflicts = MatchConflicts(defs, func(name string) (bool, bool) {
+		if !serviceKeyExists(name) {
+			return false, false
+		}
+		q := ParseSCQuery(run("sc", "query", name))
+		return true, q.State == SCStateRunning
+	}, ci.Antivirus)
+
+	return ci, err
+}
@@ -12,6 +12,7 @@ var (
 	kernel32                    = windows.NewLazySystemDLL("kernel32.dll")
 	procSetConsoleWindowInfo    = kernel32.NewProc("SetConsoleWindowInfo")
 	procSetConsoleScreenBufSize = kernel32.NewProc("SetConsoleScreenBufferSize")
+	procGetOEMCP                = kernel32.NewProc("GetOEMCP")
 )
 
 type coord struct {
@@ -38,3 +39,13 @@ func TrySetConsoleSize(cols, rows int16) {
 	_, _, _ = procSetConsoleWindowInfo.Call(uintptr(h), uintptr(1), uintptr(unsafe.Pointer(&rect)))
 	_, _, _ = procSetConsoleScreenBufSize.Call(uintptr(h), uintptr(*(*int32)(unsafe.Pointer(&buf))))
 }
+
+// consoleOutputCP is the code page console programs we start write in: the
+// console's, or the system OEM code page when we have no console.
+func consoleOutputCP() uint32 {
+	if cp, err := windows.GetConsoleOutputCP(); err == nil && cp != 0 {
+		return cp
+	}
+	cp, _, _ := procGetOEMCP.Call()
+	return uint32(cp)
+}
@@ -0,0 +1,93 @@
+package system
+
+// Win32_DeviceGuard value mappings.
+// https://learn.microsoft.com/windows/security/hardware-security/enable-virtualization-based-protection-of-code-integrity
+
+const (
+	SecurityServiceCredentialGuard = "CredentialGuard"
+	SecurityServiceHVCI            = "HVCI"
+	SecurityServiceSystemGuard     = "SystemGuard"
+)
+
+func VBSStatusName(v int) string {
+	switch v {
+	case 0:
+		return "Off"
+	case 1:
+		return "Configured"
+	case 2:
+		return "Running"
+	default:
+		return "Unknown"
+	}
+}
+
+// SecurityServiceName maps SecurityServicesConfigured/Running values.
+func SecurityServiceName(v int) string {
+	switch v {
+	case 1:
+		return SecurityServiceCredentialGuard
+	case 2:
+		return SecurityServiceHVCI
+	case 3:
+		return SecurityServiceSystemGuard
+	case 4:
+		return "SMMFirmwareMeasurement"
+	case 5:
+		return "KernelStackProtection"
+	case 6:
+		return "KernelStackProtectionAudit"
+	case 7:
+		return "HypervisorEnforcedPagingTranslation"
+	default:
+		return ""
+	}
+}
+
+// SecurityPropertyName maps Required/AvailableSecurityProperties values.
+func SecurityPropertyName(v int) string {
+	switch v {
+	case 1:
+		return "HypervisorSupport"
+	case 2:
+		return "SecureBoot"
+	case 3:
+		return "DMAProtection"
+	case 4:
+		return "SecureMemoryOverwrite"
+	case 5:
+		return "NXProtections"
+	case 6:
+		return "SMMMitigations"
+	case 7:
+		return "MBEC"
+	case 8:
+		return "APICVirtualization"
+	default:
+		return ""
+	}
+}
+
+// CIPolicyStatusName maps (UsermodeCodeIntegrity|CodeIntegrity)PolicyEnforcementStatus.
+func CIPolicyStatusName(v int) string {
+	switch v {
+	case 0:
+		return "Off"
+	case 1:
+		return "Audit"
+	case 2:
+		return "Enforced"
+	default:
+		return "Unknown"
+	}
+}
+
+func mapNames(vals []int, name func(int) string) []string {
+	var out []string
+	for _, v := range vals {
+		if n := name(v); n != "" {
+			out = append(out, n)
+		}
+	}
+	return out
+}
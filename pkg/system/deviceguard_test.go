@@ -0,0 +1,61 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeviceGuardNames(t *testing.T) {
+	for _, tc := range []struct {
+		fn   func(int) string
+		name string
+		in   int
+		want string
+	}{
+		{VBSStatusName, "VBSStatusName", 0, "Off"},
+		{VBSStatusName, "VBSStatusName", 1, "Configured"},
+		{VBSStatusName, "VBSStatusName", 2, "Running"},
+		{VBSStatusName, "VBSStatusName", 3, "Unknown"},
+		{VBSStatusName, "VBSStatusName", -1, "Unknown"},
+
+		{SecurityServiceName, "SecurityServiceName", 0, ""},
+		{SecurityServiceName, "SecurityServiceName", 1, SecurityServiceCredentialGuard},
+		{SecurityServiceName, "SecurityServiceName", 2, SecurityServiceHVCI},
+		{SecurityServiceName, "SecurityServiceName", 3, SecurityServiceSystemGuard},
+		{SecurityServiceName, "SecurityServiceName", 7, "HypervisorEnforcedPagingTranslation"},
+		{SecurityServiceName, "SecurityServiceName", 8, ""},
+
+		{SecurityPropertyName, "SecurityPropertyName", 1, "HypervisorSupport"},
+		{SecurityPropertyName, "SecurityPropertyName", 3, "DMAProtection"},
+		{SecurityPropertyName, "SecurityPropertyName", 7, "MBEC"},
+		{SecurityPropertyName, "SecurityPropertyName", 0, ""},
+		{SecurityPropertyName, "SecurityPropertyName", 9, ""},
+
+		{CIPolicyStatusName, "CIPolicyStatusName", 0, "Off"},
+		{CIPolicyStatusName, "CIPolicyStatusName", 1, "Audit"},
+		{CIPolicyStatusName, "CIPolicyStatusName", 2, "Enforced"},
+		{CIPolicyStatusName, "CIPolicyStatusName", 3, "Unknown"},
+	} {
+		if got := tc.fn(tc.in); got != tc.want {
+			t.Errorf("%s(%d) = %q, want %q", tc.name, tc.in, got, tc.want)
+		}
+	}
+}
+
+// SecurityServicesRunning/Configured arrays: 0 ("none") and unknown values
+// are dropped.
+func TestMapSecurityServices(t *testing.T) {
+	for _, tc := range []struct {
+		in   []int
+		want []string
+	}{
+		{nil, nil},
+		{[]int{0}, nil},
+		{[]int{2}, []string{SecurityServiceHVCI}},
+		{[]int{1, 2, 42}, []string{SecurityServiceCredentialGuard, SecurityServiceHVCI}},
+	} {
+		if got := mapNames(tc.in, SecurityServiceName); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("mapNames(%v) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
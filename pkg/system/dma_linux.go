@@ -0,0 +1,37 @@
+//go:build linux
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GetDMAInfo reports IOMMU units and Thunderbolt DMA protection from sysfs.
+func GetDMAInfo(VirtualizationInfo) (DMAInfo, error) {
+	return readDMAInfo("/sys")
+}
+
+func readDMAInfo(sysfs string) (DMAInfo, error) {
+	di := DMAInfo{Source: "sysfs"}
+
+	entries, err := os.ReadDir(filepath.Join(sysfs, "class", "iommu"))
+	if err != nil && !os.IsNotExist(err) {
+		return di, err
+	}
+	for _, e := range entries {
+		di.IOMMUs = append(di.IOMMUs, e.Name())
+	}
+	di.IOMMUAvailable = len(di.IOMMUs) > 0
+
+	// Set by the kernel when the firmware opted in to IOMMU DMA protection (the Linux side of Kernel DMA Protection).
+	matches, _ := filepath.Glob(filepath.Join(sysfs, "bus", "thunderbolt", "devices", "domain*", "iommu_dma_protection"))
+	for _, m := range matches {
+		if b, err := os.ReadFile(m); err == nil && strings.TrimSpace(string(b)) == "1" {
+			di.KernelDMAProtection = true
+		}
+	}
+
+	return di, nil
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package system
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadDMAInfo(t *testing.T) {
+	for _, tc := range []struct {
+		dir        string
+		iommus     []string
+		protection bool
+	}{
+		{"protected", []string{"dmar0", "dmar1"}, true},
+		{"unprotected", []string{"ivhd0"}, false},
+		{"no_iommu", nil, false},
+		{"missing", nil, false}, // no sysfs at all, e.g. a container
+	} {
+		t.Run(tc.dir, func(t *testing.T) {
+			di, err := readDMAInfo(filepath.Join("testdata", "sysfs", tc.dir))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(di.IOMMUs, tc.iommus) || di.IOMMUAvailable != (len(tc.iommus) > 0) {
+				t.Errorf("IOMMUs %v (available %v), want %v", di.IOMMUs, di.IOMMUAvailable, tc.iommus)
+			}
+			if di.KernelDMAProtection != tc.protection || di.Source != "sysfs" {
+				t.Errorf("%+v, want protection %v", di, tc.protection)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package system
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// SYSTEM_INFORMATION_CLASS SystemDmaGuardPolicyInformation
+const systemDmaGuardPolicyInformation = 202
+
+// GetDMAInfo reports Kernel DMA Protection and IOMMU availability. The IOMMU
+// comes from the Win32_DeviceGuard properties already read into virt.
+func GetDMAInfo(virt VirtualizationInfo) (DMAInfo, error) {
+	var di DMAInfo
+	var err error
+
+	// Kernel DMA Protection (same source as msinfo32)
+	var enabled byte
+	var n uint32
+	if e := windows.NtQuerySystemInformation(systemDmaGuardPolicyInformation, unsafe.Pointer(&enabled), 1, &n); e == nil {
+		di.KernelDMAProtection = enabled != 0
+		di.Source = "ntquerysysteminformation"
+	} else {
+		err = e
+	}
+
+	di.IOMMUAvailable = containsFold(virt.AvailableSecurityProperties, "DMAProtection")
+	return di, err
+}
@@ -0,0 +1,131 @@
+package system
+
+// Vulnerable / conflicting kernel driver scan.
+// Input is `driverquery /v /fo csv /nh`; columns are addressed by position
+// because the header and the state words are localized.
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed driver_blocklist.json
+var bundledDriverBlocklist []byte
+
+type DriverBlocklist struct {
+	Version string                 `json:"version"`
+	Drivers []DriverBlocklistEntry `json:"drivers"`
+}
+
+type DriverBlocklistEntry struct {
+	File   string `json:"file"`   // e.g. RTCore64.sys
+	Vendor string `json:"vendor"` // product / vendor shown to the user
+	Reason string `json:"reason"`
+}
+
+// driverquery /v /fo csv column positions.
+const (
+	dqColModule    = 0
+	dqColDisplay   = 1
+	dqColType      = 3
+	dqColStartMode = 4
+	dqColState     = 5
+	dqColPath      = 13
+)
+
+// LoadDriverBlocklist reads a blocklist file; an empty path returns the bundled one.
+func LoadDriverBlocklist(path string) (DriverBlocklist, error) {
+	raw := bundledDriverBlocklist
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return DriverBlocklist{}, err
+		}
+		raw = b
+	}
+	var bl DriverBlocklist
+	err := json.Unmarshal(raw, &bl)
+	return bl, err
+}
+
+func ParseDriverQueryCSV(r io.Reader) ([]DriverEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	var out []DriverEntry
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return out, err
+		}
+		if len(rec) <= dqColPath {
+			continue
+		}
+		out = append(out, DriverEntry{
+			Name:        strings.TrimSpace(rec[dqColModule]),
+			DisplayName: strings.TrimSpace(rec[dqColDisplay]),
+			Type:        strings.TrimSpace(rec[dqColType]),
+			StartMode:   strings.TrimSpace(rec[dqColStartMode]),
+			State:       strings.TrimSpace(rec[dqColState]),
+			Path:        strings.TrimSpace(rec[dqColPath]),
+		})
+	}
+	return out, nil
+}
+
+// ResolveDriverPath turns the path forms driverquery and service ImagePaths
+// use (\SystemRoot\..., \??\C:\..., System32\drivers\...) into a file path
+// that can be opened. systemRoot is the Windows directory.
+func ResolveDriverPath(p, systemRoot string) string {
+	p = strings.TrimPrefix(strings.TrimSpace(p), `\??\`)
+	low := strings.ToLower(p)
+	switch {
+	case strings.HasPrefix(low, `\systemroot\`):
+		return systemRoot + p[len(`\SystemRoot`):]
+	case strings.HasPrefix(low, `%systemroot%\`):
+		return systemRoot + p[len(`%SystemRoot%`):]
+	case strings.HasPrefix(low, `system32\`):
+		return systemRoot + `\` + p
+	}
+	return p
+}
+
+// MatchDrivers returns the drivers that appear in the blocklist, by file name,
+// or original file name (renamed copies).
+func MatchDrivers(drivers []DriverEntry, bl DriverBlocklist) []VulnerableDriver {
+	var out []VulnerableDriver
+	for _, d := range drivers {
+		file := winBase(d.Path)
+		for _, e := range bl.Drivers {
+			by := ""
+			switch {
+			case strings.EqualFold(file, e.File) || strings.EqualFold(d.Name+".sys", e.File):
+				by = "name"
+			case d.OrigName != "" && strings.EqualFold(d.OrigName, e.File):
+				by = "original name"
+			}
+			if by == "" {
+				continue
+			}
+			out = append(out, VulnerableDriver{
+				Name:      d.Name,
+				File:      e.File,
+				Path:      d.Path,
+				Vendor:    e.Vendor,
+				Reason:    e.Reason,
+				State:     d.State,
+				MatchedBy: by,
+			})
+			break
+		}
+	}
+	return out
+}
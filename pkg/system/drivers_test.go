@@ -0,0 +1,55 @@
+package system
+
+import (
+	"strings"
+	"testing"
+)
+
+const driverQueryCSV = `"RTCore64","RTCore64","RTCore64","Kernel ","Manual","Running","OK","TRUE","FALSE","4096","8192","0","01/01/2020 00:00:00","C:\Windows\system32\drivers\RTCore64.sys","4096"
+"hwmon","HW Monitor","HW Monitor","Kernel ","Auto","Stopped","OK","FALSE","FALSE","4096","8192","0","01/01/2020 00:00:00","C:\Windows\system32\drivers\hwmon.sys","4096"
+"disk","Disk Driver","Disk Driver","Kernel ","Boot","Running","OK","TRUE","FALSE","4096","8192","0","01/01/2020 00:00:00","C:\Windows\system32\drivers\disk.sys","4096"
+`
+
+func TestMatchDrivers(t *testing.T) {
+	drivers, err := ParseDriverQueryCSV(strings.NewReader(driverQueryCSV))
+	if err != nil || len(drivers) != 3 {
+		t.Fatalf("ParseDriverQueryCSV: %d drivers, %v", len(drivers), err)
+	}
+	if drivers[1].StartMode != "Auto" || drivers[1].State != "Stopped" {
+		t.Errorf("columns: %+v", drivers[1])
+	}
+
+	// hwmon.sys is a renamed WinRing0x64.sys.
+	drivers[1].OrigName = "WinRing0x64.sys"
+
+	bl, err := LoadDriverBlocklist("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := MatchDrivers(drivers, bl)
+	if len(got) != 2 {
+		t.Fatalf("matched %d drivers: %+v", len(got), got)
+	}
+	if got[0].File != "RTCore64.sys" || got[0].MatchedBy != "name" {
+		t.Errorf("RTCore64: %+v", got[0])
+	}
+	if got[1].File != "WinRing0x64.sys" || got[1].MatchedBy != "original name" || got[1].Name != "hwmon" {
+		t.Errorf("renamed WinRing0: %+v", got[1])
+	}
+}
+
+func TestResolveDriverPath(t *testing.T) {
+	for in, want := range map[string]string{
+		`\SystemRoot\System32\drivers\RTCore64.sys`: `C:\Windows\System32\drivers\RTCore64.sys`,
+		`\systemroot\system32\DRIVERS\disk.sys`:     `C:\Windows\system32\DRIVERS\disk.sys`,
+		`%SystemRoot%\System32\drivers\AsIO3.sys`:   `C:\Windows\System32\drivers\AsIO3.sys`,
+		`\??\C:\Program Files\Tool\WinRing0x64.sys`: `C:\Program Files\Tool\WinRing0x64.sys`,
+		`System32\drivers\hwmon.sys`:                `C:\Windows\System32\drivers\hwmon.sys`,
+		`C:\Windows\system32\drivers\RTCore64.sys`:  `C:\Windows\system32\drivers\RTCore64.sys`,
+		` D:\Drivers\NTIOLib_X64.sys `:              `D:\Drivers\NTIOLib_X64.sys`,
+	} {
+		if got := ResolveDriverPath(in, `C:\Windows`); got != want {
+			t.Errorf("ResolveDriverPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+//go:build windows
+
+package system
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ScanVulnerableDrivers lists installed kernel drivers and matches them against
+// the blocklist at blocklistPath (bundled list when empty). Loaded tells the
+// drivers the kernel is running apart from the ones that are merely installed.
+func ScanVulnerableDrivers(blocklistPath string) (DriverScan, error) {
+	bl, err := LoadDriverBlocklist(blocklistPath)
+	if err != nil {
+		return DriverScan{}, err
+	}
+
+	out, err := exec.Command("driverquery", "/v", "/fo", "csv", "/nh").Output()
+	if err != nil {
+		return DriverScan{BlocklistVersion: bl.Version}, err
+	}
+	drivers, err := ParseDriverQueryCSV(strings.NewReader(string(out)))
+
+	root := os.Getenv("SystemRoot")
+	if root == "" {
+		root = `C:\Windows`
+	}
+	for i := range drivers {
+		drivers[i].Path = ResolveDriverPath(drivers[i].Path, root)
+		if vi, verr := ReadVersionInfo(drivers[i].Path); verr == nil {
+			drivers[i].OrigName = vi.OriginalFilename
+		}
+	}
+
+	scan := DriverScan{
+		Scanned:          len(drivers),
+		BlocklistVersion: bl.Version,
+		Vulnerable:       MatchDrivers(drivers, bl),
+	}
+	for i, v := range scan.Vulnerable {
+		// driverquery's State column is localized; sc reports the numeric state.
+		scan.Vulnerable[i].Loaded = ParseSCQuery(run("sc", "query", v.Name)).State == SCStateRunning
+	}
+	return scan, err
+}
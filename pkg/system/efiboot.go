@@ -0,0 +1,276 @@
+package system
+
+// Parser for the UEFI boot manager variables: Boot#### (EFI_LOAD_OPTION),
+// BootOrder and BootCurrent. Input is the raw variable data, as returned by
+// GetFirmwareEnvironmentVariable, or an efivarfs file (4-byte attribute prefix).
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// EFI_GLOBAL_VARIABLE, as used in efivarfs file names.
+const efiGlobalGUIDLower = "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+
+// EFI_LOAD_OPTION attributes.
+const (
+	LoadOptionActive = 0x00000001
+	LoadOptionHidden = 0x00000008
+)
+
+const windowsBootManagerPath = `\EFI\Microsoft\Boot\bootmgfw.efi`
+
+var errShortLoadOption = errors.New("EFI load option too short")
+
+// ParseLoadOption decodes one Boot#### variable.
+func ParseLoadOption(data []byte) (UEFIBootEntry, error) {
+	if len(data) < 6 {
+		return UEFIBootEntry{}, errShortLoadOption
+	}
+	attrs := binary.LittleEndian.Uint32(data[0:4])
+	fpLen := int(binary.LittleEndian.Uint16(data[4:6]))
+
+	desc, n := utf16z(data[6:])
+	if n < 0 {
+		return UEFIBootEntry{}, errors.New("EFI load option: unterminated description")
+	}
+	off := 6 + n
+	if off+fpLen > len(data) {
+		return UEFIBootEntry{}, errShortLoadOption
+	}
+
+	e := UEFIBootEntry{
+		Description: desc,
+		Active:      attrs&LoadOptionActive != 0,
+		Hidden:      attrs&LoadOptionHidden != 0,
+	}
+	e.DevicePath, e.FilePath = FormatDevicePath(data[off : off+fpLen])
+	e.WindowsBootManager = isWindowsBootManager(e)
+	return e, nil
+}
+
+// ParseBootOrder decodes BootOrder (an array of UINT16 option numbers).
+func ParseBootOrder(data []byte) []uint16 {
+	out := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		out = append(out, binary.LittleEndian.Uint16(data[i:]))
+	}
+	return out
+}
+
+// ReadUEFIBootOrder reads BootOrder, BootCurrent and every Boot#### listed in
+// BootOrder through read (variable name -> raw data). Entries that cannot be
+// read are skipped and reported in the returned error.
+func ReadUEFIBootOrder(read func(name string) ([]byte, error)) (UEFIBootOrder, error) {
+	bo := UEFIBootOrder{BootCurrent: -1}
+
+	raw, err := read("BootOrder")
+	if err != nil {
+		return bo, err
+	}
+	bo.BootOrder = ParseBootOrder(raw)
+
+	if cur, err := read("BootCurrent"); err == nil && len(cur) >= 2 {
+		bo.BootCurrent = int(binary.LittleEndian.Uint16(cur))
+	}
+
+	var errs []error
+	for _, num := range bo.BootOrder {
+		name := fmt.Sprintf("Boot%04X", num)
+		data, err := read(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		e, err := ParseLoadOption(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		e.Number = num
+		e.Name = name
+		e.Current = int(num) == bo.BootCurrent
+		bo.Entries = append(bo.Entries, e)
+	}
+
+	// The firmware tries BootOrder in order and skips inactive options.
+	for _, e := range bo.Entries {
+		if e.Active {
+			bo.First = e.Description
+			bo.WindowsFirst = e.WindowsBootManager
+			break
+		}
+	}
+	return bo, errors.Join(errs...)
+}
+
+// EFIVarDirReader reads variables from a directory: efivarfs layout
+// ("BootOrder-8be4df61-...", attribute prefix stripped) or raw dumps
+// named "BootOrder" / "BootOrder.bin".
+func EFIVarDirReader(dir string) func(name string) ([]byte, error) {
+	efivarfs := efiVarDirReader(dir, efiGlobalGUIDLower)
+	return func(name string) ([]byte, error) {
+		b, err := efivarfs(name)
+		if err == nil {
+			return b, nil
+		}
+		for _, fn := range []string{name, name + ".bin"} {
+			if b, err := os.ReadFile(filepath.Join(dir, fn)); err == nil {
+				return b, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// efiVarDirReader reads efivarfs files of one vendor GUID.
+func efiVarDirReader(dir, guid string) func(name string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		b, err := os.ReadFile(filepath.Join(dir, name+"-"+guid))
+		if err != nil {
+			return nil, err
+		}
+		if len(b) < 4 {
+			return nil, fmt.Errorf("%s: short efivarfs file", name)
+		}
+		return b[4:], nil
+	}
+}
+
+// Entry returns the load option with the given number.
+func (bo UEFIBootOrder) Entry(num int) (UEFIBootEntry, bool) {
+	for _, e := range bo.Entries {
+		if int(e.Number) == num {
+			return e, true
+		}
+	}
+	return UEFIBootEntry{}, false
+}
+
+func isWindowsBootManager(e UEFIBootEntry) bool {
+	if e.FilePath != "" {
+		return strings.EqualFold(e.FilePath, windowsBootManagerPath)
+	}
+	return strings.EqualFold(e.Description, "Windows Boot Manager")
+}
+
+// FormatDevicePath renders a device path list in the UEFI text notation
+// ("PciRoot(0x0)/Pci(0x1D,0x0)/NVMe(0x1,...)/HD(1,GPT,...)/\EFI\...") and
+// returns the last file path node separately.
+func FormatDevicePath(b []byte) (text, file string) {
+	var nodes []string
+	for len(b) >= 4 {
+		typ, sub := b[0], b[1]
+		n := int(binary.LittleEndian.Uint16(b[2:4]))
+		if n < 4 || n > len(b) {
+			nodes = append(nodes, "<truncated>")
+			break
+		}
+		d := b[4:n]
+		b = b[n:]
+
+		if typ == 0x7F { // end of path
+			if sub == 0xFF {
+				break
+			}
+			nodes = append(nodes, ",") // end of instance
+			continue
+		}
+		s := devicePathNode(typ, sub, d)
+		if typ == 4 && sub == 4 {
+			file = s
+		}
+		nodes = append(nodes, s)
+	}
+	return strings.ReplaceAll(strings.Join(nodes, "/"), "/,/", ","), file
+}
+
+func devicePathNode(typ, sub byte, d []byte) string {
+	switch {
+	case typ == 1 && sub == 1 && len(d) >= 2: // PCI
+		return fmt.Sprintf("Pci(0x%X,0x%X)", d[1], d[0])
+	case typ == 1 && sub == 4 && len(d) >= 16:
+		return "VenHw(" + efiGUID(d) + ")"
+	case typ == 2 && sub == 1 && len(d) >= 8: // ACPI
+		hid := binary.LittleEndian.Uint32(d)
+		uid := binary.LittleEndian.Uint32(d[4:])
+		switch hid {
+		case 0x0A0341D0:
+			return fmt.Sprintf("PciRoot(0x%X)", uid)
+		case 0x0A0841D0:
+			return fmt.Sprintf("PcieRoot(0x%X)", uid)
+		}
+		return fmt.Sprintf("Acpi(0x%08X,0x%X)", hid, uid)
+	case typ == 3 && sub == 2 && len(d) >= 4:
+		return fmt.Sprintf("Scsi(0x%X,0x%X)", binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:]))
+	case typ == 3 && sub == 5 && len(d) >= 2:
+		return fmt.Sprintf("USB(0x%X,0x%X)", d[0], d[1])
+	case typ == 3 && sub == 11 && len(d) >= 32:
+		return fmt.Sprintf("MAC(%X)", d[:6])
+	case typ == 3 && sub == 12:
+		return "IPv4()"
+	case typ == 3 && sub == 13:
+		return "IPv6()"
+	case typ == 3 && sub == 18 && len(d) >= 6:
+		return fmt.Sprintf("Sata(0x%X,0x%X,0x%X)", binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:]), binary.LittleEndian.Uint16(d[4:]))
+	case typ == 3 && sub == 23 && len(d) >= 12:
+		return fmt.Sprintf("NVMe(0x%X,%X)", binary.LittleEndian.Uint32(d), d[4:12])
+	case typ == 3 && sub == 24:
+		return "Uri(" + string(d) + ")"
+	case typ == 3 && sub == 10 && len(d) >= 16:
+		return "VenMsg(" + efiGUID(d) + ")"
+	case typ == 4 && sub == 1 && len(d) >= 38: // hard drive partition
+		part := binary.LittleEndian.Uint32(d)
+		start := binary.LittleEndian.Uint64(d[4:])
+		size := binary.LittleEndian.Uint64(d[12:])
+		sig := d[20:36]
+		switch d[37] {
+		case 2:
+			return fmt.Sprintf("HD(%d,GPT,%s,0x%X,0x%X)", part, efiGUID(sig), start, size)
+		case 1:
+			return fmt.Sprintf("HD(%d,MBR,0x%08X,0x%X,0x%X)", part, binary.LittleEndian.Uint32(sig), start, size)
+		}
+		return fmt.Sprintf("HD(%d,0x%X,0x%X)", part, start, size)
+	case typ == 4 && sub == 2 && len(d) >= 4:
+		return fmt.Sprintf("CDROM(0x%X)", binary.LittleEndian.Uint32(d))
+	case typ == 4 && sub == 3 && len(d) >= 16:
+		return "VenMedia(" + efiGUID(d) + ")"
+	case typ == 4 && sub == 4:
+		s, _ := utf16z(d)
+		return s
+	case typ == 4 && sub == 6 && len(d) >= 16:
+		return "FvFile(" + efiGUID(d) + ")"
+	case typ == 4 && sub == 7 && len(d) >= 16:
+		return "Fv(" + efiGUID(d) + ")"
+	case typ == 5 && sub == 1 && len(d) >= 4: // legacy BIOS boot (CSM)
+		desc := strings.TrimRight(string(d[4:]), "\x00")
+		return fmt.Sprintf("BBS(0x%X,%s)", binary.LittleEndian.Uint16(d), desc)
+	}
+	return fmt.Sprintf("Path(%d,%d,%X)", typ, sub, d)
+}
+
+// efiGUID formats a mixed-endian EFI_GUID.
+func efiGUID(b []byte) string {
+	return fmt.Sprintf("%08X-%04X-%04X-%X-%X",
+		binary.LittleEndian.Uint32(b), binary.LittleEndian.Uint16(b[4:]),
+		binary.LittleEndian.Uint16(b[6:]), b[8:10], b[10:16])
+}
+
+// utf16z decodes a NUL-terminated UTF-16LE string and returns the bytes
+// consumed including the terminator, or -1 when there is none.
+func utf16z(b []byte) (string, int) {
+	var u []uint16
+	for i := 0; i+1 < len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i:])
+		if c == 0 {
+			return string(utf16.Decode(u)), i + 2
+		}
+		u = append(u, c)
+	}
+	return string(utf16.Decode(u)), -1
+}
@@ -0,0 +1,8 @@
+//go:build linux
+
+package system
+
+// GetUEFIBootOrder reads the firmware boot entries from efivarfs.
+func GetUEFIBootOrder() (UEFIBootOrder, error) {
+	return ReadUEFIBootOrder(EFIVarDirReader("/sys/firmware/efi/efivars"))
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const nvmeESP = `PciRoot(0x0)/Pci(0x1D,0x0)/NVMe(0x1,0025385B71B0A1C2)/HD(1,GPT,3F2A1B4C-5D6E-4F70-8192-A3B4C5D6E7F8,0x800,0x32000)`
+
+func readBootOrderFixture(t *testing.T, name string) (UEFIBootOrder, error) {
+	t.Helper()
+	return ReadUEFIBootOrder(EFIVarDirReader(filepath.Join("testdata", "efivars", name)))
+}
+
+func TestReadUEFIBootOrder(t *testing.T) {
+	type entry struct {
+		Name, Description, DevicePath, FilePath string
+		Active, Current, Windows                bool
+	}
+	win := entry{"Boot0000", "Windows Boot Manager", nvmeESP + `/\EFI\Microsoft\Boot\bootmgfw.efi`, windowsBootManagerPath, true, true, true}
+	notCurrent := func(e entry) entry { e.Current = false; return e }
+	pxe := entry{"Boot0002", "UEFI: PXE IPv4 Realtek", "PciRoot(0x0)/Pci(0x1C,0x5)/MAC(D8BBC1A0B1C2)/IPv4()", "", true, false, false}
+
+	tests := []struct {
+		dir          string
+		order        []uint16
+		current      int
+		first        string
+		windowsFirst bool
+		entries      []entry
+	}{
+		{"windows_first", []uint16{0, 1, 2}, 0, "Windows Boot Manager", true, []entry{
+			win,
+			{"Boot0001", "UEFI: SanDisk", "PciRoot(0x0)/Pci(0x14,0x0)/USB(0x3,0x0)/HD(1,GPT,11111111-2222-3333-4444-555555555555,0x800,0x1D00000)", "", true, false, false},
+			pxe,
+		}},
+		{"grub_first", []uint16{3, 0}, 3, "ubuntu", false, []entry{
+			{"Boot0003", "ubuntu", nvmeESP + `/\EFI\ubuntu\shimx64.efi`, `\EFI\ubuntu\shimx64.efi`, true, true, false},
+			notCurrent(win),
+		}},
+		{"inactive_first", []uint16{5, 0, 6}, 0, "Windows Boot Manager", true, []entry{
+			{"Boot0005", "Fedora", nvmeESP + `/\EFI\fedora\shimx64.efi`, `\EFI\fedora\shimx64.efi`, false, false, false},
+			win,
+			{"Boot0006", "Hard Drive", "BBS(0x2,Samsung SSD 870)", "", true, false, false},
+		}},
+		{"bootnext", []uint16{0, 2}, 7, "Windows Boot Manager", true, []entry{notCurrent(win), pxe}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			bo, err := readBootOrderFixture(t, tt.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(bo.BootOrder, tt.order) || bo.BootCurrent != tt.current ||
+				bo.First != tt.first || bo.WindowsFirst != tt.windowsFirst {
+				t.Errorf("order %v current %d first %q windowsFirst %v", bo.BootOrder, bo.BootCurrent, bo.First, bo.WindowsFirst)
+			}
+			var got []entry
+			for _, e := range bo.Entries {
+				got = append(got, entry{e.Name, e.Description, e.DevicePath, e.FilePath, e.Active, e.Current, e.WindowsBootManager})
+			}
+			if !reflect.DeepEqual(got, tt.entries) {
+				t.Errorf("entries:\n got  %+v\n want %+v", got, tt.entries)
+			}
+		})
+	}
+}
+
+// BootCurrent outside BootOrder: no entry is marked as the one booted.
+func TestReadUEFIBootOrderCurrentNotListed(t *testing.T) {
+	bo, err := readBootOrderFixture(t, "bootnext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bo.Entry(bo.BootCurrent); ok {
+		t.Errorf("Boot%04X should not be read", bo.BootCurrent)
+	}
+}
+
+func TestReadUEFIBootOrderTruncated(t *testing.T) {
+	bo, err := readBootOrderFixture(t, "truncated")
+	if err == nil || !strings.Contains(err.Error(), "Boot0001: "+errShortLoadOption.Error()) {
+		t.Errorf("err = %v", err)
+	}
+	if len(bo.Entries) != 2 || !bo.WindowsFirst {
+		t.Fatalf("entries %+v, windowsFirst %v", bo.Entries, bo.WindowsFirst)
+	}
+	if e := bo.Entries[1]; e.Name != "Boot0002" || e.DevicePath != "PciRoot(0x0)/Pci(0x14,0x0)/<truncated>" {
+		t.Errorf("cut device path node: %+v", e)
+	}
+
+	win, err := os.ReadFile(filepath.Join("testdata", "efivars", "windows_first", "Boot0000-"+efiGlobalGUIDLower))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 4; n < 4+6+42; n++ { // header and description cut short
+		if _, err := ParseLoadOption(win[4:n]); err == nil {
+			t.Errorf("%d bytes: no error", n-4)
+		}
+	}
+}
+
+func TestEFIVarDirReader(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "BootOrder.bin"), []byte{1, 0, 0, 0}, 0o644)          // raw dump
+	os.WriteFile(filepath.Join(dir, "BootCurrent-"+efiGlobalGUIDLower), []byte{7}, 0o644) // short efivarfs file
+	read := EFIVarDirReader(dir)
+
+	if b, err := read("BootOrder"); err != nil || !reflect.DeepEqual(ParseBootOrder(b), []uint16{1, 0}) {
+		t.Errorf("raw dump: %v, %v", b, err)
+	}
+	if _, err := read("BootCurrent"); err == nil {
+		t.Error("short efivarfs file: no error")
+	}
+	if _, err := read("Boot0000"); !os.IsNotExist(err) {
+		t.Errorf("missing variable: %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package system
+
+// GetUEFIBootOrder reads the firmware boot entries. Needs admin rights; fails
+// on a Legacy/CSM boot, where there are no UEFI variables.
+func GetUEFIBootOrder() (UEFIBootOrder, error) {
+	return ReadUEFIBootOrder(func(name string) ([]byte, error) {
+		return readEFIVar(name, efiGlobalGUID)
+	})
+}
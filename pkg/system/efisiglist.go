@@ -0,0 +1,147 @@
+package system
+
+// Parser for the Secure Boot signature databases (db, dbx): a sequence of
+// EFI_SIGNATURE_LIST, each holding entries of one type (SHA-256 image hash,
+// X.509 certificate, certificate TBS hash).
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"slices"
+)
+
+// EFI_IMAGE_SECURITY_DATABASE_GUID, as used in efivarfs file names.
+const efiImageSecurityGUIDLower = "d719b2cb-3d3a-4596-a3bc-dad00e67656f"
+
+// EFI_SIGNATURE_LIST types (efiGUID notation).
+const (
+	efiCertSHA256     = "C1C41626-504C-4092-ACA9-41F936934328"
+	efiCertX509       = "A5C059A1-94E4-4AA7-87B5-AB155C2BF072"
+	efiCertX509SHA256 = "3BD2A492-96C0-4079-B420-FCF98EF103ED"
+)
+
+// SignatureDB is a parsed db or dbx variable.
+type SignatureDB struct {
+	Hashes    []string            // Authenticode SHA-256 image hashes (hex)
+	Certs     []*x509.Certificate // X.509 entries
+	TBSHashes []string            // SHA-256 of revoked certificates' TBS (dbx)
+}
+
+func ParseSignatureDB(data []byte) (*SignatureDB, error) {
+	db := &SignatureDB{}
+	for len(data) > 0 {
+		if len(data) < 28 {
+			return db, errors.New("truncated EFI_SIGNATURE_LIST")
+		}
+		typ := efiGUID(data)
+		listSize := int(binary.LittleEndian.Uint32(data[16:]))
+		hdrSize := int(binary.LittleEndian.Uint32(data[20:]))
+		sigSize := int(binary.LittleEndian.Uint32(data[24:]))
+		if listSize < 28+hdrSize || listSize > len(data) || sigSize <= 16 {
+			return db, errors.New("bad EFI_SIGNATURE_LIST size")
+		}
+		entries := data[28+hdrSize : listSize]
+		data = data[listSize:]
+
+		for ; len(entries) >= sigSize; entries = entries[sigSize:] {
+			sig := entries[16:sigSize] // skip SignatureOwner
+			switch typ {
+			case efiCertSHA256:
+				db.Hashes = append(db.Hashes, hex.EncodeToString(sig))
+			case efiCertX509:
+				if c, err := x509.ParseCertificate(sig); err == nil {
+					db.Certs = append(db.Certs, c)
+				}
+			case efiCertX509SHA256:
+				if len(sig) >= 32 {
+					db.TBSHashes = append(db.TBSHashes, hex.EncodeToString(sig[:32]))
+				}
+			}
+		}
+	}
+	return db, nil
+}
+
+// SecureBootVerdict tells whether the firmware would start the image with
+// Secure Boot on. db may be nil when it could not be read.
+func SecureBootVerdict(sig PESignature, db, dbx *SignatureDB) string {
+	chain := signerChain(sig.Certs)
+	if dbx != nil && (containsFold(dbx.Hashes, sig.ImageSHA256) || dbx.revokes(chain)) {
+		return SBVerdictRevoked
+	}
+	switch {
+	case !sig.Signed:
+		return SBVerdictUnsigned
+	case !sig.Valid:
+		return SBVerdictInvalid
+	case db == nil:
+		return SBVerdictUnknown
+	case containsFold(db.Hashes, sig.ImageSHA256) || db.trusts(chain):
+		return SBVerdictAllowed
+	}
+	return SBVerdictNotTrusted
+}
+
+const (
+	SBVerdictAllowed    = "Allowed"
+	SBVerdictRevoked    = "Revoked"    // hash or certificate in dbx
+	SBVerdictNotTrusted = "NotTrusted" // valid signature, but not from a db certificate
+	SBVerdictUnsigned   = "Unsigned"
+	SBVerdictInvalid    = "Invalid" // signature does not match the file
+	SBVerdictUnknown    = "Unknown" // db not readable
+)
+
+// signerChain returns the signing certificate followed by its issuers among
+// the embedded certificates. The rest of the PKCS#7 bag is ignored: anyone can
+// add a Microsoft certificate to a signature.
+func signerChain(certs []*x509.Certificate) []*x509.Certificate {
+	if len(certs) == 0 {
+		return nil
+	}
+	chain := []*x509.Certificate{certs[0]}
+	for c := certs[0]; ; {
+		var issuer *x509.Certificate
+		for _, p := range certs {
+			if !slices.Contains(chain, p) && bytes.Equal(c.RawIssuer, p.RawSubject) && c.CheckSignatureFrom(p) == nil {
+				issuer = p
+				break
+			}
+		}
+		if issuer == nil {
+			return chain
+		}
+		chain = append(chain, issuer)
+		c = issuer
+	}
+}
+
+// trusts: a certificate of the signer chain is in db or was issued by one.
+func (db *SignatureDB) trusts(chain []*x509.Certificate) bool {
+	for _, c := range chain {
+		for _, d := range db.Certs {
+			if bytes.Equal(c.Raw, d.Raw) || c.CheckSignatureFrom(d) == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (db *SignatureDB) revokes(chain []*x509.Certificate) bool {
+	for _, c := range chain {
+		tbs := sha256.Sum256(c.RawTBSCertificate)
+		if containsFold(db.TBSHashes, hex.EncodeToString(tbs[:])) {
+			return true
+		}
+		for _, d := range db.Certs {
+			if bytes.Equal(c.Raw, d.Raw) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package system
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readSignatureDB(t *testing.T, name string) *SignatureDB {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "efisiglist", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := ParseSignatureDB(data)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return db
+}
+
+func TestParseSignatureDB(t *testing.T) {
+	if db := readSignatureDB(t, "db_ca.esl"); len(db.Certs) != 1 || db.Certs[0].Subject.CommonName != "DigiCert EV Code Signing CA (SHA2)" {
+		t.Errorf("db_ca: %+v", db)
+	}
+	db := readSignatureDB(t, "db_hash.esl")
+	if len(db.Certs) != 1 || len(db.Hashes) != 2 || len(db.TBSHashes) != 0 {
+		t.Errorf("db_hash: %d certs, %d hashes, %d TBS hashes", len(db.Certs), len(db.Hashes), len(db.TBSHashes))
+	}
+	if dbx := readSignatureDB(t, "dbx_tbs.esl"); len(dbx.TBSHashes) != 1 || len(dbx.TBSHashes[0]) != 64 {
+		t.Errorf("dbx_tbs: %+v", dbx)
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", "efisiglist", "db_hash.esl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{10, 27, len(data) - 1} {
+		if _, err := ParseSignatureDB(data[:n]); err == nil {
+			t.Errorf("cut at %d: no error", n)
+		}
+	}
+}
+
+func TestSecureBootVerdict(t *testing.T) {
+	sig, err := ReadPESignature(writeTemp(t, "bootx64.efi", readPEFixture(t, "signed.exe")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbCA, dbHash, dbOther := readSignatureDB(t, "db_ca.esl"), readSignatureDB(t, "db_hash.esl"), readSignatureDB(t, "db_other_ca.esl")
+	dbxHash, dbxTBS := readSignatureDB(t, "dbx_hash.esl"), readSignatureDB(t, "dbx_tbs.esl")
+
+	for _, tt := range []struct {
+		name    string
+		db, dbx *SignatureDB
+		want    string
+	}{
+		{"db certificate", dbCA, nil, SBVerdictAllowed},
+		{"db hash", dbHash, dbOther, SBVerdictAllowed},
+		{"dbx hash", dbCA, dbxHash, SBVerdictRevoked},
+		{"dbx TBS hash", dbHash, dbxTBS, SBVerdictRevoked},
+		{"other CA", dbOther, nil, SBVerdictNotTrusted},
+		{"db unreadable", nil, dbOther, SBVerdictUnknown},
+		{"db unreadable, dbx hash", nil, dbxHash, SBVerdictRevoked},
+	} {
+		if got := SecureBootVerdict(sig, tt.db, tt.dbx); got != tt.want {
+			t.Errorf("%s: %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// A db certificate merely bundled in the PKCS#7 bag does not vouch for the signer.
+	bag := sig
+	bag.Certs = append(append(bag.Certs[:0:0], sig.Certs...), dbOther.Certs[0])
+	if got := SecureBootVerdict(bag, dbOther, nil); got != SBVerdictNotTrusted {
+		t.Errorf("unrelated bundled certificate: %s, want %s", got, SBVerdictNotTrusted)
+	}
+	if got := SecureBootVerdict(bag, dbCA, nil); got != SBVerdictAllowed {
+		t.Errorf("bundled certificate and db issuer: %s, want %s", got, SBVerdictAllowed)
+	}
+
+	if got := SecureBootVerdict(PESignature{}, dbCA, nil); got != SBVerdictUnsigned {
+		t.Errorf("unsigned: %s", got)
+	}
+	invalid := sig
+	invalid.Valid = false
+	if got := SecureBootVerdict(invalid, dbCA, nil); got != SBVerdictInvalid {
+		t.Errorf("invalid: %s", got)
+	}
+}
+
+func TestSignerChain(t *testing.T) {
+	sig, err := ReadPESignature(writeTemp(t, "bootx64.efi", readPEFixture(t, "signed.exe")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := readSignatureDB(t, "db_other_ca.esl").Certs[0]
+	// The unrelated certificate sits between the signer and its issuer.
+	chain := signerChain([]*x509.Certificate{sig.Certs[0], other, sig.Certs[1]})
+	if len(chain) != 2 || chain[0] != sig.Certs[0] || chain[1] != sig.Certs[1] {
+		t.Errorf("chain: %v", chain)
+	}
+	if signerChain(nil) != nil {
+		t.Error("no certificates: non-nil chain")
+	}
+}
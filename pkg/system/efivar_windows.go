@@ -0,0 +1,68 @@
+//go:build windows
+
+package system
+
+import (
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// EFI_GLOBAL_VARIABLE
+const efiGlobalGUID = "{8BE4DF61-93CA-11D2-AA0D-00E098032B8C}"
+
+// EFI_IMAGE_SECURITY_DATABASE_GUID (db, dbx)
+const efiImageSecurityGUID = "{D719B2CB-3D3A-4596-A3BC-DAD00E67656F}"
+
+var (
+	procGetFirmwareEnvironmentVariableW = kernel32.NewProc("GetFirmwareEnvironmentVariableW")
+	enableSysEnvOnce                    sync.Once
+)
+
+// readEFIVar reads a UEFI variable. On a Legacy/CSM boot it fails with
+// ERROR_INVALID_FUNCTION; without admin rights with ERROR_PRIVILEGE_NOT_HELD.
+func readEFIVar(name, guid string) ([]byte, error) {
+	enableSysEnvOnce.Do(enableSystemEnvironmentPrivilege)
+
+	n, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	g, err := windows.UTF16PtrFromString(guid)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		r, _, e := procGetFirmwareEnvironmentVariableW.Call(
+			uintptr(unsafe.Pointer(n)), uintptr(unsafe.Pointer(g)),
+			uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+		if r != 0 {
+			return buf[:r], nil
+		}
+		if e == windows.ERROR_INSUFFICIENT_BUFFER && len(buf) < 1<<20 {
+			buf = make([]byte, len(buf)*4)
+			continue
+		}
+		return nil, e
+	}
+}
+
+func enableSystemEnvironmentPrivilege() {
+	var tok windows.Token
+	if err := windows.OpenProcessToken(windows.CurrentProcess(), windows.TOKEN_ADJUST_PRIVILEGES|windows.TOKEN_QUERY, &tok); err != nil {
+		return
+	}
+	defer tok.Close()
+
+	var luid windows.LUID
+	name, _ := windows.UTF16PtrFromString("SeSystemEnvironmentPrivilege")
+	if err := windows.LookupPrivilegeValue(nil, name, &luid); err != nil {
+		return
+	}
+	tp := windows.Tokenprivileges{PrivilegeCount: 1}
+	tp.Privileges[0] = windows.LUIDAndAttributes{Luid: luid, Attributes: windows.SE_PRIVILEGE_ENABLED}
+	_ = windows.AdjustTokenPrivileges(tok, false, &tp, 0, nil, nil)
+}
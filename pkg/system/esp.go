@@ -0,0 +1,53 @@
+package system
+
+// EFI System Partition inventory: every .efi binary with its signer and the
+// Secure Boot verdict against the firmware db/dbx.
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// ScanESP walks a mounted ESP (e.g. "S:\" after `mountvol S: /s`, or
+// /boot/efi). db and dbx may be nil when they could not be read.
+func ScanESP(root string, db, dbx *SignatureDB) (ESPInventory, error) {
+	inv := ESPInventory{Root: root, DBRead: db != nil}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil // unreadable subfolder: keep going
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".efi") {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		b := ESPBinary{Path: rel}
+
+		sig, err := ReadPESignature(path)
+		if err != nil {
+			b.Signature = PESignature{Error: "not a valid PE file: " + err.Error()}
+			b.SecureBoot = SBVerdictInvalid
+		} else {
+			b.Signature = sig
+			b.SecureBoot = SecureBootVerdict(sig, db, dbx)
+		}
+		inv.Binaries = append(inv.Binaries, b)
+		return nil
+	})
+	return inv, err
+}
+
+// readSignatureDBs reads db and dbx through read; either is nil when missing.
+func readSignatureDBs(read func(name string) ([]byte, error)) (db, dbx *SignatureDB) {
+	if b, err := read("db"); err == nil {
+		db, _ = ParseSignatureDB(b)
+	}
+	if b, err := read("dbx"); err == nil {
+		dbx, _ = ParseSignatureDB(b)
+	}
+	return db, dbx
+}
@@ -0,0 +1,9 @@
+//go:build linux
+
+package system
+
+// GetESPInventory scans an ESP mounted at root (usually /boot/efi).
+func GetESPInventory(root string) (ESPInventory, error) {
+	db, dbx := readSignatureDBs(efiVarDirReader("/sys/firmware/efi/efivars", efiImageSecurityGUIDLower))
+	return ScanESP(root, db, dbx)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package system
+
+// GetESPInventory scans an ESP mounted at root (`mountvol S: /s` as admin).
+func GetESPInventory(root string) (ESPInventory, error) {
+	db, dbx := readSignatureDBs(func(name string) ([]byte, error) {
+		return readEFIVar(name, efiImageSecurityGUID)
+	})
+	return ScanESP(root, db, dbx)
+}
@@ -0,0 +1,173 @@
+package system
+
+// Picks the event log records that matter for Vanguard out of a System log:
+// service control manager failures of vgc/vgk, Secure Boot events of
+// Kernel-Boot and TPM-WMI, and bugchecks.
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	EventCategoryVanguard   = "Vanguard service"
+	EventCategorySecureBoot = "Secure Boot"
+	EventCategoryTPM        = "TPM"
+	EventCategoryBugCheck   = "BugCheck"
+)
+
+const maxEventLogEvents = 50
+
+// Service Control Manager failure events. The service name is param1.
+var scmFailures = map[int]string{
+	7000: "failed to start",
+	7001: "depends on a service that failed to start",
+	7009: "timed out connecting to the service control manager",
+	7011: "timed out answering a control request",
+	7022: "hung on starting",
+	7023: "terminated with an error",
+	7024: "terminated with a service-specific error",
+	7026: "boot or system start driver failed to load",
+	7031: "terminated unexpectedly",
+	7034: "terminated unexpectedly",
+	7043: "did not shut down properly",
+}
+
+// Microsoft-Windows-TPM-WMI Secure Boot servicing events (2023 CA update).
+var tpmWMIEvents = map[int]string{
+	1795: "firmware rejected a Secure Boot variable update",
+	1796: "Secure Boot variable update failed",
+	1801: "Secure Boot certificates need to be updated",
+	1808: "Secure Boot certificates updated",
+}
+
+var reMessageRef = regexp.MustCompile(`%%(\d+)`)
+
+// AnalyzeEVTX reads an .evtx file and keeps the relevant events.
+func AnalyzeEVTX(path string) (EventLogReport, error) {
+	recs, err := ReadEVTX(path)
+	rep := FilterEventLog(recs)
+	rep.Source = path
+	return rep, err
+}
+
+// FilterEventLog classifies recs and returns the relevant ones, newest first.
+func FilterEventLog(recs []EventRecord) EventLogReport {
+	rep := EventLogReport{Records: len(recs)}
+	for i := len(recs) - 1; i >= 0 && len(rep.Events) < maxEventLogEvents; i-- {
+		if ev, ok := ClassifyEvent(recs[i]); ok {
+			rep.Events = append(rep.Events, ev)
+		}
+	}
+	return rep
+}
+
+// ClassifyEvent reports whether rec is relevant and describes it.
+func ClassifyEvent(rec EventRecord) (EventLogEvent, bool) {
+	ev := EventLogEvent{EventRecord: rec}
+	switch rec.Provider {
+	case "Service Control Manager":
+		what, ok := scmFailures[rec.EventID]
+		if !ok || !mentionsVanguard(rec) {
+			return ev, false
+		}
+		ev.Category = EventCategoryVanguard
+		ev.Summary = strings.TrimSpace(firstNonEmpty(rec.Field("param1"), dataValue(rec, 0)) + " " + what)
+		if e := scmError(rec); e != "" {
+			ev.Summary += " (" + e + ")"
+		}
+
+	case "Microsoft-Windows-Kernel-Boot":
+		if !mentionsSecureBoot(rec) {
+			return ev, false
+		}
+		ev.Category = EventCategorySecureBoot
+		ev.Summary = formatEventData(rec)
+
+	case "Microsoft-Windows-TPM-WMI":
+		ev.Category = EventCategoryTPM
+		ev.Summary = tpmWMIEvents[rec.EventID]
+		if d := formatEventData(rec); d != "" {
+			ev.Summary = strings.TrimPrefix(ev.Summary+": "+d, ": ")
+		}
+
+	case "Microsoft-Windows-WER-SystemErrorReporting", "BugCheck":
+		if rec.EventID != 1001 {
+			return ev, false
+		}
+		ev.Category = EventCategoryBugCheck
+		ev.Summary = "bugcheck " + firstNonEmpty(rec.Field("param1"), dataValue(rec, 0))
+
+	case "Microsoft-Windows-Kernel-Power":
+		code := rec.Field("BugcheckCode")
+		if rec.EventID != 41 || code == "" || code == "0" {
+			return ev, false
+		}
+		ev.Category = EventCategoryBugCheck
+		if n, err := strconv.ParseUint(code, 0, 64); err == nil {
+			code = fmt.Sprintf("0x%08X", n)
+		}
+		ev.Summary = "unexpected reboot after bugcheck " + code
+
+	default:
+		return ev, false
+	}
+	return ev, true
+}
+
+func mentionsVanguard(rec EventRecord) bool {
+	for _, d := range rec.Data {
+		for _, w := range strings.FieldsFunc(strings.ToLower(d.Value), func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+		}) {
+			if w == "vgc" || w == "vgk" || w == "vanguard" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mentionsSecureBoot(rec EventRecord) bool {
+	for _, d := range rec.Data {
+		s := strings.ToLower(strings.ReplaceAll(d.Name+" "+d.Value, " ", ""))
+		if strings.Contains(s, "secureboot") {
+			return true
+		}
+	}
+	return false
+}
+
+// scmError returns the error insert of an SCM failure ("%%1058" -> "error 1058").
+func scmError(rec EventRecord) string {
+	for _, d := range rec.Data[min(1, len(rec.Data)):] {
+		if m := reMessageRef.FindStringSubmatch(d.Value); m != nil {
+			return "error " + m[1]
+		}
+	}
+	return ""
+}
+
+func dataValue(rec EventRecord, i int) string {
+	if i < len(rec.Data) {
+		return rec.Data[i].Value
+	}
+	return ""
+}
+
+func formatEventData(rec EventRecord) string {
+	var parts []string
+	for _, d := range rec.Data {
+		if d.Value == "" {
+			continue
+		}
+		if d.Name == "" {
+			parts = append(parts, d.Value)
+		} else {
+			parts = append(parts, d.Name+"="+d.Value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
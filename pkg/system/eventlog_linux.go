@@ -0,0 +1,11 @@
+//go:build linux
+
+package system
+
+import "errors"
+
+// GetEventLogReport is Windows only; exported .evtx files can still be read
+// with AnalyzeEVTX.
+func GetEventLogReport() (EventLogReport, error) {
+	return EventLogReport{}, errors.New("the Windows event log is not available on this system")
+}
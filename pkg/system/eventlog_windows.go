@@ -0,0 +1,34 @@
+//go:build windows
+
+package system
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GetEventLogReport exports the System log with wevtutil (needs an elevated
+// prompt) and keeps the events relevant to Vanguard and Secure Boot.
+func GetEventLogReport() (EventLogReport, error) {
+	dir, err := os.MkdirTemp("", "vsc-evtx")
+	if err != nil {
+		return EventLogReport{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "System.evtx")
+	out, err := exec.Command("wevtutil", "epl", "System", file).CombinedOutput()
+	if err != nil {
+		if s := strings.TrimSpace(string(out)); s != "" {
+			return EventLogReport{}, errors.New("wevtutil failed (run as administrator): " + firstLine(s))
+		}
+		return EventLogReport{}, err
+	}
+
+	rep, err := AnalyzeEVTX(file)
+	rep.Source = "System"
+	return rep, err
+}
@@ -0,0 +1,624 @@
+package system
+
+// Windows XML Event Log (.evtx) reader. The file is a 4 KiB header followed by
+// 64 KiB chunks; each chunk holds event records encoded as Binary XML, whose
+// element names and template definitions are shared inside the chunk. We decode
+// the BinXML into a small element tree and keep the fields we need.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	evtxFileMagic   = "ElfFile\x00"
+	evtxChunkMagic  = "ElfChnk\x00"
+	evtxHeaderSize  = 0x1000
+	evtxChunkSize   = 0x10000
+	evtxRecordsOff  = 0x200
+	evtxRecordMagic = 0x00002a2a // "**\0\0"
+)
+
+// BinXML tokens. 0x40 on element/value/attribute tokens means "more follows".
+const (
+	bxEOF           = 0x00
+	bxOpenStart     = 0x01
+	bxCloseStart    = 0x02
+	bxCloseEmpty    = 0x03
+	bxEndElement    = 0x04
+	bxValue         = 0x05
+	bxAttribute     = 0x06
+	bxCDATA         = 0x07
+	bxCharRef       = 0x08
+	bxEntityRef     = 0x09
+	bxPITarget      = 0x0A
+	bxPIData        = 0x0B
+	bxTemplate      = 0x0C
+	bxSubstitution  = 0x0D
+	bxOptionalSubst = 0x0E
+	bxFragment      = 0x0F
+	bxMoreBit       = 0x40
+)
+
+// BinXML value types; 0x80 marks an array of the base type.
+const (
+	bvNull       = 0x00
+	bvString     = 0x01
+	bvAnsiString = 0x02
+	bvInt8       = 0x03
+	bvUInt8      = 0x04
+	bvInt16      = 0x05
+	bvUInt16     = 0x06
+	bvInt32      = 0x07
+	bvUInt32     = 0x08
+	bvInt64      = 0x09
+	bvUInt64     = 0x0A
+	bvReal32     = 0x0B
+	bvReal64     = 0x0C
+	bvBool       = 0x0D
+	bvBinary     = 0x0E
+	bvGUID       = 0x0F
+	bvSizeT      = 0x10
+	bvFileTime   = 0x11
+	bvSysTime    = 0x12
+	bvSID        = 0x13
+	bvHexInt32   = 0x14
+	bvHexInt64   = 0x15
+	bvBinXML     = 0x21
+	bvArray      = 0x80
+)
+
+// Element, template and embedded BinXML nesting allowed in one record. Real
+// events stay far below; a template or value that contains itself would
+// otherwise recurse until the stack overflows.
+const maxBinXMLDepth = 32
+
+var (
+	errBinXMLTruncated = errors.New("truncated BinXML")
+	errBinXMLDepth     = errors.New("BinXML nested too deep (self-referencing template?)")
+)
+
+// ReadEVTX reads every record of an .evtx file (e.g. exported with
+// `wevtutil epl System system.evtx`).
+func ReadEVTX(path string) ([]EventRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEVTX(data)
+}
+
+// ParseEVTX decodes the records of an .evtx file, oldest first. Records that
+// cannot be decoded are reported in the error; the others are still returned.
+func ParseEVTX(data []byte) ([]EventRecord, error) {
+	if len(data) < evtxHeaderSize || string(data[:8]) != evtxFileMagic {
+		return nil, errors.New("not an EVTX file")
+	}
+
+	var recs []EventRecord
+	var errs []error
+	for off := evtxHeaderSize; off+evtxRecordsOff <= len(data); off += evtxChunkSize {
+		chunk := data[off:min(off+evtxChunkSize, len(data))]
+		if len(chunk) < evtxChunkSize {
+			// Copied while still being written: keep the complete records.
+			errs = append(errs, fmt.Errorf("chunk at 0x%X: file truncated", off))
+		}
+		if string(chunk[:8]) != evtxChunkMagic {
+			continue // never used
+		}
+		r, err := parseEVTXChunk(chunk)
+		recs = append(recs, r...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("chunk at 0x%X: %w", off, err))
+		}
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].RecordID < recs[j].RecordID })
+	return recs, errors.Join(errs...)
+}
+
+func parseEVTXChunk(c []byte) ([]EventRecord, error) {
+	end := int(binary.LittleEndian.Uint32(c[48:])) // free space offset
+	if end < evtxRecordsOff || end > len(c) {
+		end = len(c)
+	}
+
+	var recs []EventRecord
+	var errs []error
+	for off := evtxRecordsOff; off+28 <= end; {
+		if binary.LittleEndian.Uint32(c[off:]) != evtxRecordMagic {
+			break
+		}
+		size := int(binary.LittleEndian.Uint32(c[off+4:]))
+		if size < 28 || off+size > end {
+			break
+		}
+		rec := EventRecord{
+			RecordID: binary.LittleEndian.Uint64(c[off+8:]),
+			Time:     fileTime(binary.LittleEndian.Uint64(c[off+16:])),
+		}
+		root := &xmlNode{}
+		bx := binXML{chunk: c}
+		if _, _, err := bx.parse(off+24, off+size-4, nil, root); err != nil {
+			errs = append(errs, fmt.Errorf("record %d: %w", rec.RecordID, err))
+		} else {
+			rec.fill(root)
+		}
+		recs = append(recs, rec)
+		off += size
+	}
+	return recs, errors.Join(errs...)
+}
+
+// xmlNode is a decoded BinXML element.
+type xmlNode struct {
+	name     string
+	attrs    map[string]string
+	children []*xmlNode
+	text     string
+}
+
+func (n *xmlNode) child(name string) *xmlNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) childText(name string) string {
+	if c := n.child(name); c != nil {
+		return strings.TrimSpace(c.text)
+	}
+	return ""
+}
+
+func (n *xmlNode) childAttr(name, attr string) string {
+	if c := n.child(name); c != nil {
+		return c.attrs[attr]
+	}
+	return ""
+}
+
+// binValue is one substitution value of a template instance.
+type binValue struct {
+	typ       byte
+	off, size int
+}
+
+type binXML struct {
+	chunk []byte
+	depth int // nested parse calls: elements, templates, BinXML values
+}
+
+// parse decodes tokens from off into parent until EOF, an end element or end.
+// It returns the offset after the last token and the token that stopped it.
+func (b binXML) parse(off, end int, subs []binValue, parent *xmlNode) (int, byte, error) {
+	if b.depth >= maxBinXMLDepth {
+		return off, 0, errBinXMLDepth
+	}
+	b.depth++
+
+	c := b.chunk
+	if end > len(c) {
+		end = len(c)
+	}
+	for off < end {
+		tok := c[off]
+		switch tok &^ bxMoreBit {
+		case bxEOF:
+			return off + 1, bxEOF, nil
+		case bxEndElement:
+			return off + 1, bxEndElement, nil
+		case bxFragment:
+			off += 4
+		case bxOpenStart:
+			el, next, err := b.element(off, end, subs)
+			if err != nil {
+				return next, 0, err
+			}
+			parent.children = append(parent.children, el)
+			off = next
+		case bxValue, bxCharRef, bxEntityRef, bxCDATA:
+			s, next, err := b.text(off, end, subs)
+			if err != nil {
+				return next, 0, err
+			}
+			parent.text += s
+			off = next
+		case bxSubstitution, bxOptionalSubst:
+			if off+4 > end {
+				return off, 0, errBinXMLTruncated
+			}
+			id := int(binary.LittleEndian.Uint16(c[off+1:]))
+			if id < len(subs) && subs[id].typ == bvBinXML {
+				v := subs[id]
+				if _, _, err := b.parse(v.off, v.off+v.size, nil, parent); err != nil {
+					return off, 0, err
+				}
+			} else if id < len(subs) {
+				parent.text += b.value(subs[id])
+			}
+			off += 4
+		case bxTemplate:
+			next, err := b.template(off, end, parent)
+			if err != nil {
+				return next, 0, err
+			}
+			off = next
+		case bxPITarget:
+			_, next, err := b.name(off+1, off+5)
+			if err != nil {
+				return next, 0, err
+			}
+			off = next
+		case bxPIData:
+			if off+3 > end {
+				return off, 0, errBinXMLTruncated
+			}
+			off += 3 + 2*int(binary.LittleEndian.Uint16(c[off+1:]))
+		default:
+			return off, 0, fmt.Errorf("unknown BinXML token 0x%02X at 0x%X", tok, off)
+		}
+	}
+	return off, bxEOF, nil
+}
+
+// element decodes an open start element token, its attributes and content.
+func (b binXML) element(off, end int, subs []binValue) (*xmlNode, int, error) {
+	c := b.chunk
+	if off+11 > end {
+		return nil, off, errBinXMLTruncated
+	}
+	start := off
+	hasAttrs := c[off]&bxMoreBit != 0
+	p := off + 11 // token, dependency id, data size, name offset
+	if hasAttrs {
+		p += 4 // attribute list size
+	}
+	name, p, err := b.name(start+7, p)
+	if err != nil {
+		return nil, p, err
+	}
+	el := &xmlNode{name: name}
+
+	for p < end && c[p]&^bxMoreBit == bxAttribute {
+		an, next, err := b.name(p+1, p+5)
+		if err != nil {
+			return nil, next, err
+		}
+		var val string
+		for next < end && isBinXMLText(c[next]) {
+			s, n, err := b.text(next, end, subs)
+			if err != nil {
+				return nil, n, err
+			}
+			val += s
+			next = n
+		}
+		if el.attrs == nil {
+			el.attrs = map[string]string{}
+		}
+		el.attrs[an] = val
+		p = next
+	}
+
+	if p >= end {
+		return nil, p, errBinXMLTruncated
+	}
+	switch c[p] {
+	case bxCloseEmpty:
+		return el, p + 1, nil
+	case bxCloseStart:
+		next, tok, err := b.parse(p+1, end, subs, el)
+		if err != nil {
+			return nil, next, err
+		}
+		if tok != bxEndElement {
+			return nil, next, fmt.Errorf("element %s not closed", name)
+		}
+		return el, next, nil
+	}
+	return nil, p, fmt.Errorf("unexpected BinXML token 0x%02X after element %s", c[p], name)
+}
+
+func isBinXMLText(tok byte) bool {
+	switch tok &^ bxMoreBit {
+	case bxValue, bxCharRef, bxEntityRef, bxCDATA, bxSubstitution, bxOptionalSubst:
+		return true
+	}
+	return false
+}
+
+// text decodes one character data token (or a substitution) as a string.
+func (b binXML) text(off, end int, subs []binValue) (string, int, error) {
+	c := b.chunk
+	if off+2 > end {
+		return "", off, errBinXMLTruncated
+	}
+	switch c[off] &^ bxMoreBit {
+	case bxValue:
+		if c[off+1] != bvString {
+			return "", off, fmt.Errorf("unsupported BinXML value type 0x%02X", c[off+1])
+		}
+		return b.utf16(off+2, end)
+	case bxCDATA:
+		return b.utf16(off+1, end)
+	case bxCharRef:
+		if off+3 > end {
+			return "", off, errBinXMLTruncated
+		}
+		return string(rune(binary.LittleEndian.Uint16(c[off+1:]))), off + 3, nil
+	case bxEntityRef:
+		name, next, err := b.name(off+1, off+5)
+		if err != nil {
+			return "", next, err
+		}
+		ent := map[string]string{"amp": "&", "lt": "<", "gt": ">", "quot": `"`, "apos": "'"}
+		if s, ok := ent[name]; ok {
+			return s, next, nil
+		}
+		return "&" + name + ";", next, nil
+	case bxSubstitution, bxOptionalSubst:
+		if off+4 > end {
+			return "", off, errBinXMLTruncated
+		}
+		id := int(binary.LittleEndian.Uint16(c[off+1:]))
+		if id < len(subs) {
+			return b.value(subs[id]), off + 4, nil
+		}
+		return "", off + 4, nil
+	}
+	return "", off, fmt.Errorf("unexpected BinXML token 0x%02X", c[off])
+}
+
+// utf16 reads a length-prefixed (in characters) UTF-16 string at off.
+func (b binXML) utf16(off, end int) (string, int, error) {
+	if off+2 > end {
+		return "", off, errBinXMLTruncated
+	}
+	n := 2 * int(binary.LittleEndian.Uint16(b.chunk[off:]))
+	if off+2+n > end {
+		return "", off, errBinXMLTruncated
+	}
+	s, _ := utf16z(b.chunk[off+2 : off+2+n])
+	return s, off + 2 + n, nil
+}
+
+// name reads the name string referenced at ref. Names are stored once per
+// chunk; the first use carries the string inline, right at p.
+func (b binXML) name(ref, p int) (string, int, error) {
+	c := b.chunk
+	if ref+4 > len(c) {
+		return "", p, errBinXMLTruncated
+	}
+	at := int(binary.LittleEndian.Uint32(c[ref:]))
+	if at+8 > len(c) {
+		return "", p, errBinXMLTruncated
+	}
+	n := 2 * int(binary.LittleEndian.Uint16(c[at+6:]))
+	if at+8+n > len(c) {
+		return "", p, errBinXMLTruncated
+	}
+	s, _ := utf16z(c[at+8 : at+8+n])
+	if at == p {
+		p += 10 + n // next offset, hash, length, chars, NUL
+	}
+	return s, p, nil
+}
+
+// template decodes a template instance: the template definition (inline on
+// first use in the chunk) filled with the substitution values that follow.
+func (b binXML) template(off, end int, parent *xmlNode) (int, error) {
+	c := b.chunk
+	if off+10 > end {
+		return off, errBinXMLTruncated
+	}
+	def := int(binary.LittleEndian.Uint32(c[off+6:]))
+	p := off + 10
+	if def+24 > len(c) {
+		return p, errBinXMLTruncated
+	}
+	size := int(binary.LittleEndian.Uint32(c[def+20:]))
+	body := def + 24
+	if body+size > len(c) {
+		return p, errBinXMLTruncated
+	}
+	if def == p {
+		p = body + size
+	}
+
+	if p+4 > end {
+		return p, errBinXMLTruncated
+	}
+	n := int(binary.LittleEndian.Uint32(c[p:]))
+	p += 4
+	if n > (end-p)/4 {
+		return p, errBinXMLTruncated
+	}
+	subs := make([]binValue, n)
+	data := p + 4*n
+	for i := range subs {
+		d := p + 4*i
+		subs[i] = binValue{typ: c[d+2], off: data, size: int(binary.LittleEndian.Uint16(c[d:]))}
+		data += subs[i].size
+	}
+	if data > end {
+		return data, errBinXMLTruncated
+	}
+
+	if _, _, err := b.parse(body, body+size, subs, parent); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// value renders a substitution value as text.
+func (b binXML) value(v binValue) string {
+	d := b.chunk[v.off : v.off+v.size]
+	if v.typ&bvArray != 0 {
+		base := v.typ &^ bvArray
+		if base == bvString {
+			var parts []string
+			for len(d) >= 2 {
+				s, n := utf16z(d)
+				parts = append(parts, s)
+				if n < 0 {
+					break
+				}
+				d = d[n:]
+			}
+			return strings.Join(parts, ", ")
+		}
+		if w := binValueWidth(base); w > 0 {
+			var parts []string
+			for ; len(d) >= w; d = d[w:] {
+				parts = append(parts, formatBinValue(base, d[:w]))
+			}
+			return strings.Join(parts, ", ")
+		}
+	}
+	return formatBinValue(v.typ, d)
+}
+
+func binValueWidth(typ byte) int {
+	switch typ {
+	case bvInt8, bvUInt8:
+		return 1
+	case bvInt16, bvUInt16:
+		return 2
+	case bvInt32, bvUInt32, bvReal32, bvBool, bvHexInt32:
+		return 4
+	case bvInt64, bvUInt64, bvReal64, bvFileTime, bvHexInt64:
+		return 8
+	case bvGUID, bvSysTime:
+		return 16
+	}
+	return 0
+}
+
+func formatBinValue(typ byte, d []byte) string {
+	if w := binValueWidth(typ); w > len(d) {
+		return ""
+	}
+	le := binary.LittleEndian
+	switch typ {
+	case bvNull:
+		return ""
+	case bvString:
+		s, _ := utf16z(d)
+		return s
+	case bvAnsiString:
+		return strings.TrimRight(string(d), "\x00")
+	case bvInt8:
+		return strconv.Itoa(int(int8(d[0])))
+	case bvUInt8:
+		return strconv.Itoa(int(d[0]))
+	case bvInt16:
+		return strconv.Itoa(int(int16(le.Uint16(d))))
+	case bvUInt16:
+		return strconv.Itoa(int(le.Uint16(d)))
+	case bvInt32:
+		return strconv.Itoa(int(int32(le.Uint32(d))))
+	case bvUInt32:
+		return strconv.FormatUint(uint64(le.Uint32(d)), 10)
+	case bvInt64:
+		return strconv.FormatInt(int64(le.Uint64(d)), 10)
+	case bvUInt64:
+		return strconv.FormatUint(le.Uint64(d), 10)
+	case bvReal32:
+		return strconv.FormatFloat(float64(math.Float32frombits(le.Uint32(d))), 'g', -1, 32)
+	case bvReal64:
+		return strconv.FormatFloat(math.Float64frombits(le.Uint64(d)), 'g', -1, 64)
+	case bvBool:
+		return strconv.FormatBool(le.Uint32(d) != 0)
+	case bvGUID:
+		return "{" + efiGUID(d) + "}"
+	case bvSizeT, bvHexInt32, bvHexInt64:
+		switch len(d) {
+		case 4:
+			return fmt.Sprintf("0x%x", le.Uint32(d))
+		case 8:
+			return fmt.Sprintf("0x%x", le.Uint64(d))
+		}
+	case bvFileTime:
+		return fileTime(le.Uint64(d)).UTC().Format(time.RFC3339Nano)
+	case bvSysTime:
+		t := time.Date(int(le.Uint16(d)), time.Month(le.Uint16(d[2:])), int(le.Uint16(d[6:])),
+			int(le.Uint16(d[8:])), int(le.Uint16(d[10:])), int(le.Uint16(d[12:])),
+			int(le.Uint16(d[14:]))*int(time.Millisecond), time.UTC)
+		return t.Format(time.RFC3339Nano)
+	case bvSID:
+		return formatSID(d)
+	}
+	return fmt.Sprintf("%X", d)
+}
+
+func formatSID(d []byte) string {
+	if len(d) < 8 || len(d) < 8+4*int(d[1]) {
+		return fmt.Sprintf("%X", d)
+	}
+	var auth uint64
+	for _, x := range d[2:8] {
+		auth = auth<<8 | uint64(x)
+	}
+	s := fmt.Sprintf("S-%d-%d", d[0], auth)
+	for i := 0; i < int(d[1]); i++ {
+		s += "-" + strconv.FormatUint(uint64(binary.LittleEndian.Uint32(d[8+4*i:])), 10)
+	}
+	return s
+}
+
+// fileTime converts a FILETIME (100 ns ticks since 1601) to local time.
+func fileTime(ft uint64) time.Time {
+	if ft == 0 {
+		return time.Time{}
+	}
+	const epochDiff = 116444736000000000 // 1601 -> 1970 in 100 ns ticks
+	return time.Unix(0, int64(ft-epochDiff)*100).Local()
+}
+
+// fill copies the System, EventData and UserData fields of the decoded event.
+func (rec *EventRecord) fill(root *xmlNode) {
+	ev := root.child("Event")
+	if ev == nil {
+		return
+	}
+	if sys := ev.child("System"); sys != nil {
+		rec.Provider = sys.childAttr("Provider", "Name")
+		rec.EventID, _ = strconv.Atoi(sys.childText("EventID"))
+		rec.Level, _ = strconv.Atoi(sys.childText("Level"))
+		rec.Channel = sys.childText("Channel")
+		rec.Computer = sys.childText("Computer")
+		if t, err := time.Parse(time.RFC3339Nano, sys.childAttr("TimeCreated", "SystemTime")); err == nil {
+			rec.Time = t.Local()
+		}
+	}
+	if ed := ev.child("EventData"); ed != nil {
+		for _, d := range ed.children {
+			rec.Data = append(rec.Data, EventData{Name: d.attrs["Name"], Value: strings.TrimSpace(d.text)})
+		}
+	}
+	if ud := ev.child("UserData"); ud != nil && len(ud.children) > 0 {
+		for _, d := range ud.children[0].children {
+			rec.Data = append(rec.Data, EventData{Name: d.name, Value: strings.TrimSpace(d.text)})
+		}
+	}
+}
+
+// Field returns the named EventData/UserData value.
+func (rec EventRecord) Field(name string) string {
+	for _, d := range rec.Data {
+		if strings.EqualFold(d.Name, name) {
+			return d.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package system
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readEVTXFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "evtx", "system.evtx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseEVTX(t *testing.T) {
+	recs, err := ParseEVTX(readEVTXFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 4 {
+		t.Fatalf("got %d records, want 4", len(recs))
+	}
+
+	scm := recs[0]
+	if scm.RecordID != 1 || scm.Provider != "Service Control Manager" || scm.EventID != 7000 ||
+		scm.Level != 2 || scm.Channel != "System" || scm.Computer != "DESKTOP-TEST" {
+		t.Errorf("record 1: %+v", scm)
+	}
+	if scm.Field("param1") != "vgc" || scm.Field("param2") != "%%1058" {
+		t.Errorf("record 1 data: %+v", scm.Data)
+	}
+	if scm.Time.IsZero() || !recs[1].Time.After(scm.Time) {
+		t.Errorf("record times: %v, %v", scm.Time, recs[1].Time)
+	}
+	if got := recs[2].Field("BugcheckCode"); got != "313" {
+		t.Errorf("BugcheckCode = %q, want 313", got)
+	}
+	if got := recs[3].Field("BucketId"); got != "bucket-42" {
+		t.Errorf("UserData from embedded BinXML: %+v", recs[3].Data)
+	}
+
+	rep := FilterEventLog(recs)
+	want := []struct{ category, summary string }{
+		{EventCategoryTPM, "Secure Boot certificates need to be updated: Status=Updated certificates are not yet applied, BucketId=bucket-42"},
+		{EventCategoryBugCheck, "unexpected reboot after bugcheck 0x00000139"},
+		{EventCategoryVanguard, "vgc failed to start (error 1058)"},
+	}
+	if rep.Records != 4 || len(rep.Events) != len(want) {
+		t.Fatalf("report: %+v", rep)
+	}
+	for i, w := range want {
+		if ev := rep.Events[i]; ev.Category != w.category || ev.Summary != w.summary {
+			t.Errorf("event %d = %s %q, want %s %q", i, ev.Category, ev.Summary, w.category, w.summary)
+		}
+	}
+}
+
+// A file cut short keeps the records that were written completely.
+func TestParseEVTXTruncated(t *testing.T) {
+	data := readEVTXFixture(t)
+	full, _ := ParseEVTX(data)
+	chunk := data[evtxHeaderSize:]
+	recEnd := func(i int) int { // end of record i (0-based) in the file
+		off := evtxRecordsOff
+		for ; i >= 0; i-- {
+			off += int(binary.LittleEndian.Uint32(chunk[off+4:]))
+		}
+		return evtxHeaderSize + off
+	}
+
+	for n := evtxHeaderSize + evtxRecordsOff; n < len(data); n += 7 {
+		recs, err := ParseEVTX(data[:n])
+		if err == nil {
+			t.Fatalf("cut at 0x%X: no error", n)
+		}
+		complete := 0
+		for complete < len(full) && recEnd(complete) <= n {
+			complete++
+		}
+		if len(recs) != complete {
+			t.Fatalf("cut at 0x%X: got %d records, want %d", n, len(recs), complete)
+		}
+	}
+
+	if _, err := ParseEVTX(data[:evtxHeaderSize-1]); err == nil {
+		t.Error("short header: no error")
+	}
+
+	// BinXML cut inside the template instance of the first record.
+	rec := evtxRecordsOff
+	size := int(binary.LittleEndian.Uint32(chunk[rec+4:]))
+	for end := rec + 29; end < rec+size-5; end++ {
+		if _, _, err := (binXML{chunk: chunk}).parse(rec+24, end, nil, &xmlNode{}); !errors.Is(err, errBinXMLTruncated) {
+			t.Fatalf("BinXML cut at 0x%X: err = %v", end, err)
+		}
+	}
+}
+
+// evtxChunk wraps BinXML into a chunk holding a single record.
+func evtxChunk(bx []byte) []byte {
+	c := make([]byte, evtxChunkSize)
+	copy(c, evtxChunkMagic)
+	size := 24 + len(bx) + 4
+	r := c[evtxRecordsOff:]
+	binary.LittleEndian.PutUint32(r, evtxRecordMagic)
+	binary.LittleEndian.PutUint32(r[4:], uint32(size))
+	binary.LittleEndian.PutUint64(r[8:], 1)
+	copy(r[24:], bx)
+	binary.LittleEndian.PutUint32(r[size-4:], uint32(size))
+	binary.LittleEndian.PutUint32(c[48:], uint32(evtxRecordsOff+size))
+	return c
+}
+
+// templateInstance encodes a template instance token referencing def.
+func templateInstance(def int) []byte {
+	b := []byte{bxTemplate, 1, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(b[6:], uint32(def))
+	return b
+}
+
+func le32(v int) []byte { return binary.LittleEndian.AppendUint32(nil, uint32(v)) }
+
+func TestParseEVTXCyclic(t *testing.T) {
+	bxStart := evtxRecordsOff + 24
+	fragment := []byte{bxFragment, 1, 1, 0}
+
+	// A template whose body instantiates itself.
+	def := bxStart + len(fragment) + 10
+	body := append(templateInstance(def), le32(0)...) // no values
+	body = append(body, bxEOF)
+	bx := append(append([]byte(nil), fragment...), templateInstance(def)...)
+	bx = append(bx, le32(0)...)          // next definition
+	bx = append(bx, make([]byte, 16)...) // GUID
+	bx = append(bx, le32(len(body))...)
+	bx = append(bx, body...)
+	bx = append(bx, le32(0)...) // values of the outer instance
+	bx = append(bx, bxEOF)
+	if _, err := parseEVTXChunk(evtxChunk(bx)); !errors.Is(err, errBinXMLDepth) {
+		t.Errorf("self-referencing template: err = %v", err)
+	}
+
+	// A template that substitutes a BinXML value, instantiated again inside
+	// that value, over and over.
+	body = []byte{bxSubstitution, 0, 0, bvBinXML, bxEOF}
+	inner := append(templateInstance(def), le32(0)...)
+	for range 40 {
+		v := append(templateInstance(def), le32(1)...)
+		v = append(v, byte(len(inner)), byte(len(inner)>>8), bvBinXML, 0)
+		inner = append(v, inner...)
+	}
+	bx = append(append([]byte(nil), fragment...), templateInstance(def)...)
+	bx = append(bx, le32(0)...)
+	bx = append(bx, make([]byte, 16)...)
+	bx = append(bx, le32(len(body))...)
+	bx = append(bx, body...)
+	bx = append(bx, le32(1)...)
+	bx = append(bx, byte(len(inner)), byte(len(inner)>>8), bvBinXML, 0)
+	bx = append(bx, inner...)
+	bx = append(bx, bxEOF)
+	if _, err := parseEVTXChunk(evtxChunk(bx)); !errors.Is(err, errBinXMLDepth) {
+		t.Errorf("nested BinXML values: err = %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package system
+
+// Exploit Protection (process mitigation) policy, as exported by
+// `Get-ProcessMitigation -RegistryConfigFilePath file.xml`:
+//
+//	<MitigationPolicy>
+//	  <SystemConfig><DEP Enable="true" .../><ControlFlowGuard Enable="true" .../>...</SystemConfig>
+//	  <AppConfig Executable="vgc.exe"><ControlFlowGuard Enable="false" OverrideCFG="true"/></AppConfig>
+//	</MitigationPolicy>
+//
+// Vanguard refuses to run when DEP or Control Flow Guard is turned off, either
+// system-wide or for vgc.exe / the game.
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// System DEP policies (bcdedit nx, Win32_OperatingSystem.DataExecutionPrevention_SupportPolicy).
+// AlwaysOff disables DEP for every process, whatever the mitigation policy says.
+const (
+	DEPAlwaysOff = "AlwaysOff"
+	DEPAlwaysOn  = "AlwaysOn"
+	DEPOptIn     = "OptIn"
+	DEPOptOut    = "OptOut"
+)
+
+// Executables whose per-process overrides we report.
+var mitigationProcesses = []string{"vgc.exe", winBase(valorantShippingExe)}
+
+// Mitigations that must stay on for Vanguard.
+var requiredMitigations = []struct{ element, label string }{
+	{"DEP", "DEP"},
+	{"ControlFlowGuard", "Control Flow Guard"},
+}
+
+type xmlMitigationPolicy struct {
+	System xmlMitigationSet   `xml:"SystemConfig"`
+	Apps   []xmlMitigationSet `xml:"AppConfig"`
+}
+
+type xmlMitigationSet struct {
+	Executable string `xml:"Executable,attr"`
+	Items      []struct {
+		XMLName xml.Name
+		Attrs   []xml.Attr `xml:",any,attr"`
+	} `xml:",any"`
+}
+
+// ReadExploitProtection parses an exported Exploit Protection XML file.
+func ReadExploitProtection(path string) (ExploitProtection, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ExploitProtection{}, err
+	}
+	defer f.Close()
+
+	ep, err := ParseExploitProtection(f)
+	ep.Source = path
+	return ep, err
+}
+
+// ParseExploitProtection reads the system-wide mitigations and the overrides
+// of vgc.exe and the game, and lists the configurations known to break them.
+func ParseExploitProtection(r io.Reader) (ExploitProtection, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ExploitProtection{}, err
+	}
+	// Files saved from PowerShell 5 with Out-File are UTF-16 with a BOM.
+	if len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE {
+		s, _ := utf16z(data[2:])
+		data = []byte(s)
+	}
+
+	var doc xmlMitigationPolicy
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = func(_ string, in io.Reader) (io.Reader, error) { return in, nil }
+	if err := dec.Decode(&doc); err != nil {
+		return ExploitProtection{}, err
+	}
+
+	ep := ExploitProtection{Read: true, System: mitigationList(doc.System)}
+	for _, app := range doc.Apps {
+		for _, exe := range mitigationProcesses {
+			if strings.EqualFold(winBase(app.Executable), exe) {
+				ep.Processes = append(ep.Processes, ProcessMitigations{Executable: exe, Mitigations: mitigationList(app)})
+			}
+		}
+	}
+
+	for _, req := range requiredMitigations {
+		if mitigationState(ep.System, req.element) == "off" {
+			ep.Problems = append(ep.Problems, req.label+" is turned off system-wide")
+		}
+		for _, p := range ep.Processes {
+			if mitigationState(p.Mitigations, req.element) == "off" {
+				ep.Problems = append(ep.Problems, req.label+" is turned off for "+p.Executable)
+			}
+		}
+	}
+	return ep, nil
+}
+
+func mitigationList(set xmlMitigationSet) []Mitigation {
+	var out []Mitigation
+	for _, it := range set.Items {
+		m := Mitigation{Name: it.XMLName.Local, Settings: map[string]string{}}
+		for _, a := range it.Attrs {
+			m.Settings[a.Name.Local] = a.Value
+		}
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// mitigationState returns "on", "off" or "" (not configured, or a
+// per-process entry that does not override the system setting).
+func mitigationState(list []Mitigation, name string) string {
+	for _, m := range list {
+		if !strings.EqualFold(m.Name, name) {
+			continue
+		}
+		overrides, overridden := 0, false
+		for k, v := range m.Settings {
+			if strings.HasPrefix(k, "Override") {
+				overrides++
+				overridden = overridden || strings.EqualFold(v, "true")
+			}
+		}
+		if overrides > 0 && !overridden {
+			return ""
+		}
+		switch strings.ToLower(m.Settings["Enable"]) {
+		case "true":
+			return "on"
+		case "false":
+			return "off"
+		}
+	}
+	return ""
+}
+
+// DEPPolicyName maps Win32_OperatingSystem.DataExecutionPrevention_SupportPolicy.
+func DEPPolicyName(policy uint8) string {
+	switch policy {
+	case 0:
+		return DEPAlwaysOff
+	case 1:
+		return DEPAlwaysOn
+	case 2:
+		return DEPOptIn
+	case 3:
+		return DEPOptOut
+	}
+	return ""
+}
+
+// Disabled reports whether a mitigation ("DEP", "ControlFlowGuard") is turned
+// off system-wide or for vgc.exe / the game.
+func (ep ExploitProtection) Disabled(name string) bool {
+	if strings.EqualFold(name, "DEP") && ep.DEPPolicy == DEPAlwaysOff {
+		return true
+	}
+	if mitigationState(ep.System, name) == "off" {
+		return true
+	}
+	for _, p := range ep.Processes {
+		if mitigationState(p.Mitigations, name) == "off" {
+			return true
+		}
+	}
+	return false
+}
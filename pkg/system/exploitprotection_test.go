@@ -0,0 +1,64 @@
+package system
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadExploitProtection(t *testing.T) {
+	ep, err := ReadExploitProtection(filepath.Join("testdata", "exploit", "vgc_cfg_off.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ep.Read || len(ep.System) != 5 {
+		t.Fatalf("system mitigations: %+v", ep.System)
+	}
+	var procs []string
+	for _, p := range ep.Processes {
+		procs = append(procs, p.Executable)
+	}
+	if want := []string{"vgc.exe", "VALORANT-Win64-Shipping.exe"}; !reflect.DeepEqual(procs, want) {
+		t.Errorf("processes = %v, want %v", procs, want)
+	}
+	// The game's DEP entry does not override the system setting.
+	if want := []string{"Control Flow Guard is turned off for vgc.exe"}; !reflect.DeepEqual(ep.Problems, want) {
+		t.Errorf("problems = %q, want %q", ep.Problems, want)
+	}
+	if ep.Disabled("DEP") || !ep.Disabled("ControlFlowGuard") {
+		t.Errorf("DEP disabled %v, CFG disabled %v", ep.Disabled("DEP"), ep.Disabled("ControlFlowGuard"))
+	}
+
+	ep.DEPPolicy = DEPAlwaysOff
+	if !ep.Disabled("DEP") {
+		t.Error("nx AlwaysOff: DEP not reported disabled")
+	}
+}
+
+func TestReadExploitProtectionUTF16(t *testing.T) {
+	ep, err := ReadExploitProtection(filepath.Join("testdata", "exploit", "dep_off_utf16.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"DEP is turned off system-wide"}; !reflect.DeepEqual(ep.Problems, want) {
+		t.Errorf("problems = %q, want %q", ep.Problems, want)
+	}
+	if !ep.Disabled("DEP") || ep.Disabled("ControlFlowGuard") {
+		t.Errorf("DEP disabled %v, CFG disabled %v", ep.Disabled("DEP"), ep.Disabled("ControlFlowGuard"))
+	}
+}
+
+func TestParseExploitProtectionInvalid(t *testing.T) {
+	if _, err := ParseExploitProtection(strings.NewReader("<MitigationPolicy><SystemConfig>")); err == nil {
+		t.Error("truncated XML: no error")
+	}
+}
+
+func TestDEPPolicyName(t *testing.T) {
+	for policy, want := range map[uint8]string{0: DEPAlwaysOff, 1: DEPAlwaysOn, 2: DEPOptIn, 3: DEPOptOut, 9: ""} {
+		if got := DEPPolicyName(policy); got != want {
+			t.Errorf("DEPPolicyName(%d) = %q, want %q", policy, got, want)
+		}
+	}
+}
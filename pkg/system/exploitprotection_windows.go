@@ -0,0 +1,48 @@
+//go:build windows
+
+package system
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/StackExchange/wmi"
+)
+
+type osDEPPolicy struct {
+	DataExecutionPrevention_SupportPolicy uint8
+}
+
+// GetExploitProtection exports the Exploit Protection settings with
+// Get-ProcessMitigation and parses them. The boot-time DEP policy comes from
+// Win32_OperatingSystem; the exported policy does not show nx AlwaysOff.
+func GetExploitProtection() (ExploitProtection, error) {
+	var dep []osDEPPolicy
+	depErr := wmi.Query("SELECT DataExecutionPrevention_SupportPolicy FROM Win32_OperatingSystem", &dep)
+	policy := ""
+	if depErr == nil && len(dep) > 0 {
+		policy = DEPPolicyName(dep[0].DataExecutionPrevention_SupportPolicy)
+	}
+
+	dir, err := os.MkdirTemp("", "vsc-mitigation")
+	if err != nil {
+		return ExploitProtection{DEPPolicy: policy}, errors.Join(depErr, err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "mitigation.xml")
+	script := "Get-ProcessMitigation -RegistryConfigFilePath '" + strings.ReplaceAll(file, "'", "''") + "'"
+	if _, stderr, err := runPS64(script); err != nil || stderr != "" {
+		if stderr != "" {
+			err = errors.New("Get-ProcessMitigation failed: " + firstLine(stderr))
+		}
+		return ExploitProtection{DEPPolicy: policy}, errors.Join(depErr, err)
+	}
+
+	ep, err := ReadExploitProtection(file)
+	ep.Source = "Get-ProcessMitigation"
+	ep.DEPPolicy = policy
+	return ep, errors.Join(depErr, err)
+}
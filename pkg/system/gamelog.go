@@ -0,0 +1,220 @@
+package system
+
+// Valorant (Unreal Engine) log analysis.
+// %LOCALAPPDATA%\VALORANT\Saved\Logs\ShooterGame.log holds the last game
+// session (older ones are rotated to ShooterGame-backup-*.log); crash reports
+// go to Saved\Crashes\<folder> with a CrashContext.runtime-xml and a minidump.
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxGameLogLines = 10
+	maxGameCrashes  = 5
+)
+
+var (
+	// [2024.05.01-12.34.56:789][  0]LogInit: ... (UTC)
+	reUETimestamp = regexp.MustCompile(`^\[(\d{4}\.\d{2}\.\d{2}-\d{2}\.\d{2}\.\d{2}):\d{3}\]`)
+	// Log file open, 05/01/24 12:34:56 (local time)
+	reUELogOpen = regexp.MustCompile(`Log file open, (\d{2}/\d{2}/\d{2} \d{2}:\d{2}:\d{2})`)
+	// The client prints codes as "VAL 43"; only taken from error/code lines.
+	reVALCode = regexp.MustCompile(`\bVAL ?(\d{1,4})\b`)
+)
+
+// Lines that only appear when the game went down: the critical error block
+// and the fatal error handler. Plain "LogWindows: Error:" lines, ensures and
+// failed asserts that the engine survives are not crashes.
+var gameFatalMarkers = []string{
+	"=== critical error: ===",
+	"fatal error!",
+	"fatal error: [file:",
+	"unhandled exception: exception_",
+}
+
+// D3D device removed / hung / reset, by name or DXGI_ERROR_* code.
+var gameGPULostMarkers = []string{
+	"dxgi_error_device_removed",
+	"dxgi_error_device_hung",
+	"dxgi_error_device_reset",
+	"0x887a0005",
+	"0x887a0006",
+	"0x887a0007",
+	"gpu crashed or d3d device removed",
+	"d3d device being lost",
+}
+
+var gameAntiCheatMarkers = []string{
+	"vanguard",
+	"vgc",
+	"anticheat",
+	"anti-cheat",
+}
+
+// AnalyzeGameLogs summarizes ShooterGame.log and the newest crash reports under
+// localAppData\VALORANT\Saved (localAppData defaults to %LOCALAPPDATA%).
+func AnalyzeGameLogs(localAppData string) (GameLogSummary, error) {
+	if localAppData == "" {
+		localAppData = os.Getenv("LOCALAPPDATA")
+	}
+	if localAppData == "" {
+		return GameLogSummary{}, errors.New("LOCALAPPDATA not set")
+	}
+	saved := filepath.Join(localAppData, "VALORANT", "Saved")
+
+	var sum GameLogSummary
+	file := filepath.Join(saved, "Logs", "ShooterGame.log")
+	f, err := os.Open(file)
+	if err == nil {
+		sum, err = ParseShooterGameLog(f)
+		f.Close()
+		sum.File = file
+	} else if os.IsNotExist(err) {
+		err = nil
+	}
+
+	sum.Crashes = ReadGameCrashes(filepath.Join(saved, "Crashes"))
+	return sum, err
+}
+
+// ParseShooterGameLog reads one Unreal log (one game session).
+func ParseShooterGameLog(r io.Reader) (GameLogSummary, error) {
+	var sum GameLogSummary
+	var logOpen time.Time
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(sc.Text(), "\ufeff"))
+		if line == "" {
+			continue
+		}
+		sum.Found = true
+		sum.Lines++
+		low := strings.ToLower(line)
+
+		if m := reUELogOpen.FindStringSubmatch(line); m != nil && logOpen.IsZero() {
+			logOpen, _ = time.ParseInLocation("01/02/06 15:04:05", m[1], time.Local)
+		}
+		if m := reUETimestamp.FindStringSubmatch(line); m != nil {
+			if t, err := time.ParseInLocation("2006.01.02-15.04.05", m[1], time.UTC); err == nil {
+				t = t.Local()
+				if sum.SessionStart.IsZero() {
+					sum.SessionStart = t
+				}
+				sum.SessionEnd = t
+			}
+		}
+
+		codes := false
+		for _, m := range reVANCode.FindAllStringSubmatch(line, -1) {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				sum.ErrorCodes = appendUnique(sum.ErrorCodes, "VAN "+strconv.Itoa(n))
+				codes = true
+			}
+		}
+		for _, m := range reVALCode.FindAllStringSubmatch(line, -1) {
+			if !strings.Contains(low, "error") && !strings.Contains(low, "code") {
+				break
+			}
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				sum.ErrorCodes = appendUnique(sum.ErrorCodes, "VAL "+strconv.Itoa(n))
+				codes = true
+			}
+		}
+
+		switch {
+		case containsAny(low, gameGPULostMarkers):
+			sum.GPUDeviceLost = appendCapped(sum.GPUDeviceLost, line)
+		case containsAny(low, gameFatalMarkers):
+			sum.Fatal = appendCapped(sum.Fatal, line)
+		case containsAny(low, gameAntiCheatMarkers) && (codes || isDisconnect(low)):
+			sum.AntiCheat = appendCapped(sum.AntiCheat, line)
+		}
+	}
+	if sum.SessionStart.IsZero() {
+		sum.SessionStart = logOpen
+	}
+	return sum, sc.Err()
+}
+
+type crashContext struct {
+	RuntimeProperties struct {
+		CrashType    string `xml:"CrashType"`
+		ErrorMessage string `xml:"ErrorMessage"`
+	} `xml:"RuntimeProperties"`
+}
+
+// ReadGameCrashes lists the newest crash report folders, newest first.
+func ReadGameCrashes(dir string) []GameCrash {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []GameCrash
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		c := GameCrash{Folder: filepath.Join(dir, e.Name()), Time: info.ModTime()}
+		files, _ := os.ReadDir(c.Folder)
+		for _, f := range files {
+			name := strings.ToLower(f.Name())
+			switch {
+			case strings.HasSuffix(name, ".dmp"):
+				c.HasDump = true
+			case name == "crashcontext.runtime-xml":
+				if b, err := os.ReadFile(filepath.Join(c.Folder, f.Name())); err == nil {
+					var cc crashContext
+					if xml.Unmarshal(b, &cc) == nil {
+						c.Type = cc.RuntimeProperties.CrashType
+						c.Error = strings.TrimSpace(cc.RuntimeProperties.ErrorMessage)
+					}
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Time.After(out[j].Time) })
+	if len(out) > maxGameCrashes {
+		out = out[:maxGameCrashes]
+	}
+	return out
+}
+
+func isDisconnect(low string) bool {
+	return strings.Contains(low, "disconnect") || strings.Contains(low, "kicked") ||
+		strings.Contains(low, "not initialized") || strings.Contains(low, "connection lost") ||
+		strings.Contains(low, "failed to connect")
+}
+
+func containsAny(low string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(low, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendCapped(list []string, line string) []string {
+	if len(list) >= maxGameLogLines {
+		return list
+	}
+	return append(list, line)
+}
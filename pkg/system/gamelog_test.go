@@ -0,0 +1,63 @@
+package system
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeGameLogs(t *testing.T) {
+	sum, err := AnalyzeGameLogs(filepath.Join("testdata", "localappdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sum.Found || sum.Lines != 16 {
+		t.Fatalf("Found=%v Lines=%d", sum.Found, sum.Lines)
+	}
+	if want := time.Date(2026, 3, 2, 20, 5, 12, 0, time.UTC); !sum.SessionStart.Equal(want) {
+		t.Errorf("SessionStart = %v, want %v", sum.SessionStart, want)
+	}
+	if want := time.Date(2026, 3, 2, 20, 58, 31, 0, time.UTC); !sum.SessionEnd.Equal(want) {
+		t.Errorf("SessionEnd = %v, want %v", sum.SessionEnd, want)
+	}
+
+	// "val 3" in a config line is not an error code.
+	if want := []string{"VAL 43", "VAN 152"}; !reflect.DeepEqual(sum.ErrorCodes, want) {
+		t.Errorf("ErrorCodes = %v, want %v", sum.ErrorCodes, want)
+	}
+
+	// LogWindows errors, ensures and recovered asserts are not fatal.
+	if len(sum.Fatal) != 3 || !strings.Contains(sum.Fatal[0], "=== Critical error: ===") {
+		t.Errorf("Fatal = %q", sum.Fatal)
+	}
+	// "Input device removed" is not a GPU device.
+	if len(sum.GPUDeviceLost) != 1 || !strings.Contains(sum.GPUDeviceLost[0], "0x887A0006") {
+		t.Errorf("GPUDeviceLost = %q", sum.GPUDeviceLost)
+	}
+	if len(sum.AntiCheat) != 2 {
+		t.Errorf("AntiCheat = %q", sum.AntiCheat)
+	}
+
+	if len(sum.Crashes) != 1 {
+		t.Fatalf("Crashes = %+v", sum.Crashes)
+	}
+	c := sum.Crashes[0]
+	if c.Type != "Crash" || !c.HasDump || !strings.HasPrefix(c.Error, "Unhandled Exception: EXCEPTION_ACCESS_VIOLATION") {
+		t.Errorf("crash: %+v", c)
+	}
+}
+
+func TestParseShooterGameLogClean(t *testing.T) {
+	log := "[2026.03.02-20.05.12:345][  0]LogWindows: Error: Failed to load optional module\r\n" +
+		"[2026.03.02-20.05.13:002][  0]LogCore: Assertion failed: Index >= 0 (recovered)\r\n" +
+		"[2026.03.02-20.05.14:000][  0]LogShooter: Display: vgc error reporting disabled\r\n"
+	sum, err := ParseShooterGameLog(strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sum.Fatal)+len(sum.GPUDeviceLost)+len(sum.AntiCheat)+len(sum.ErrorCodes) != 0 {
+		t.Fatalf("clean session flagged: %+v", sum)
+	}
+}
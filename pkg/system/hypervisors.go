@@ -0,0 +1,101 @@
+package system
+
+// Windows optional features that start the Microsoft hypervisor (or depend on
+// it) and third-party hypervisors, so users know which switch to turn off and
+// what they give up. Definitions live in hypervisors.json.
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+//go:embed hypervisors.json
+var hypervisorDefinitionsJSON []byte
+
+type HypervisorDefinitions struct {
+	Features []struct {
+		Name       string `json:"name"` // optional feature name (Get-WindowsOptionalFeature)
+		Title      string `json:"title"`
+		Hypervisor bool   `json:"hypervisor"` // enabling it alone starts the hypervisor
+		Provides   string `json:"provides"`
+	} `json:"features"`
+	Products []struct {
+		Name     string   `json:"name"`
+		Services []string `json:"services"`
+		Note     string   `json:"note"`
+	} `json:"products"`
+}
+
+func LoadHypervisorDefinitions() (HypervisorDefinitions, error) {
+	var defs HypervisorDefinitions
+	err := json.Unmarshal(hypervisorDefinitionsJSON, &defs)
+	return defs, err
+}
+
+// BuildHypervisorInventory applies defs to the optional feature states
+// (feature name -> Enabled / Disabled / Absent, "" when unknown) and the
+// installed services.
+func BuildHypervisorInventory(defs HypervisorDefinitions, feature func(name string) string, service func(name string) (exists, running bool)) HypervisorInventory {
+	var inv HypervisorInventory
+	for _, f := range defs.Features {
+		state := feature(f.Name)
+		inv.Features = append(inv.Features, HypervisorFeature{
+			Name:       f.Name,
+			Title:      f.Title,
+			State:      state,
+			Enabled:    state == FeatureEnabled,
+			Hypervisor: f.Hypervisor,
+			Provides:   f.Provides,
+		})
+	}
+	for _, p := range defs.Products {
+		for _, name := range p.Services {
+			if exists, running := service(name); exists {
+				inv.Products = append(inv.Products, HypervisorProduct{Name: p.Name, Source: name, Running: running, Note: p.Note})
+				break
+			}
+		}
+	}
+	return inv
+}
+
+// Optional feature states (Win32_OptionalFeature InstallState).
+const (
+	FeatureEnabled  = "Enabled"
+	FeatureDisabled = "Disabled"
+	FeatureAbsent   = "Absent"
+)
+
+// FeatureStateName maps Win32_OptionalFeature.InstallState.
+func FeatureStateName(installState uint32) string {
+	switch installState {
+	case 1:
+		return FeatureEnabled
+	case 2:
+		return FeatureDisabled
+	case 3:
+		return FeatureAbsent
+	}
+	return ""
+}
+
+// Feature returns the inventory entry of an optional feature.
+func (inv HypervisorInventory) Feature(name string) (HypervisorFeature, bool) {
+	for _, f := range inv.Features {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return HypervisorFeature{}, false
+}
+
+// HypervisorFeaturesOn returns the enabled features that start the hypervisor.
+func (inv HypervisorInventory) HypervisorFeaturesOn() []HypervisorFeature {
+	var out []HypervisorFeature
+	for _, f := range inv.Features {
+		if f.Enabled && f.Hypervisor {
+			out = append(out, f)
+		}
+	}
+	return out
+}
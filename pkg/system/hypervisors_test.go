@@ -0,0 +1,81 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeatureStateName(t *testing.T) {
+	for in, want := range map[uint32]string{
+		1: FeatureEnabled,
+		2: FeatureDisabled,
+		3: FeatureAbsent,
+		0: "",
+		4: "", // unknown state
+	} {
+		if got := FeatureStateName(in); got != want {
+			t.Errorf("FeatureStateName(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBuildHypervisorInventory(t *testing.T) {
+	defs, err := LoadHypervisorDefinitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	features := map[string]string{
+		"Microsoft-Hyper-V-All":             FeatureDisabled,
+		"VirtualMachinePlatform":            FeatureEnabled,
+		"Microsoft-Windows-Subsystem-Linux": FeatureEnabled,
+		"HypervisorPlatform":                FeatureAbsent,
+		"Some-Future-Feature":               FeatureEnabled, // not in the definitions
+	}
+	services := map[string]bool{ // name -> running
+		"VBoxSup":   true,
+		"IntelHaxm": false,
+	}
+	inv := BuildHypervisorInventory(defs,
+		func(name string) string { return features[name] },
+		func(name string) (bool, bool) { running, ok := services[name]; return ok, running })
+
+	if len(inv.Features) != len(defs.Features) {
+		t.Fatalf("%d features, want one per definition (%d)", len(inv.Features), len(defs.Features))
+	}
+	if _, ok := inv.Feature("Some-Future-Feature"); ok {
+		t.Error("feature without a definition in the inventory")
+	}
+	for name, want := range map[string]string{
+		"VirtualMachinePlatform":        FeatureEnabled,
+		"Microsoft-Hyper-V-All":         FeatureDisabled,
+		"HypervisorPlatform":            FeatureAbsent,
+		"Containers-DisposableClientVM": "", // state unknown
+	} {
+		f, ok := inv.Feature(name)
+		if !ok || f.State != want || f.Enabled != (want == FeatureEnabled) {
+			t.Errorf("%s: %+v, want state %q", name, f, want)
+		}
+	}
+
+	// WSL is enabled but does not start the hypervisor by itself.
+	var on []string
+	for _, f := range inv.HypervisorFeaturesOn() {
+		on = append(on, f.Name)
+	}
+	if !reflect.DeepEqual(on, []string{"VirtualMachinePlatform"}) {
+		t.Errorf("HypervisorFeaturesOn = %v", on)
+	}
+
+	want := []HypervisorProduct{
+		{Name: "Oracle VirtualBox", Source: "VBoxSup", Running: true},
+		{Name: "Intel HAXM", Source: "IntelHaxm"},
+	}
+	if len(inv.Products) != len(want) {
+		t.Fatalf("products %+v", inv.Products)
+	}
+	for i, p := range inv.Products {
+		if p.Name != want[i].Name || p.Source != want[i].Source || p.Running != want[i].Running {
+			t.Errorf("product %d = %+v, want %+v", i, p, want[i])
+		}
+	}
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package system
+
+import (
+	"strings"
+
+	"github.com/StackExchange/wmi"
+)
+
+type optionalFeature struct {
+	Name         string
+	InstallState uint32
+}
+
+// GetHypervisorInventory lists the hypervisor-related optional features and
+// the third-party hypervisors installed.
+func GetHypervisorInventory() (HypervisorInventory, error) {
+	defs, err := LoadHypervisorDefinitions()
+	if err != nil {
+		return HypervisorInventory{}, err
+	}
+
+	var where []string
+	for _, f := range defs.Features {
+		where = append(where, "Name = '"+f.Name+"'")
+	}
+	var rows []optionalFeature
+	err = wmi.Query("SELECT Name, InstallState FROM Win32_OptionalFeature WHERE "+strings.Join(where, " OR "), &rows)
+	states := map[string]string{}
+	for _, r := range rows {
+		states[strings.ToLower(r.Name)] = FeatureStateName(r.InstallState)
+	}
+
+	inv := BuildHypervisorInventory(defs, func(name string) string {
+		if err != nil {
+			return ""
+		}
+		if s, ok := states[strings.ToLower(name)]; ok {
+			return s
+		}
+		return FeatureAbsent // not offered on this edition
+	}, func(name string) (bool, bool) {
+		if !serviceKeyExists(name) {
+			return false, false
+		}
+		q := ParseSCQuery(run("sc", "query", name))
+		return true, q.State == SCStateRunning
+	})
+	return inv, err
+}
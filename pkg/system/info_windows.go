@@ -6,15 +6,17 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/StackExchange/wmi"
+	"golang.org/x/sys/windows/registry"
 )
 
 type win32_Processor struct{ Name string }
 type win32_VideoController struct{ Name string }
 type win32_ComputerSystem struct{ TotalPhysicalMemory string }
 type win32_BaseBoard struct{ Manufacturer, Product string }
-type win32_OperatingSystem struct{ Caption string }
+type win32_OperatingSystem struct{ Caption, OSArchitecture string }
 
 func GetSystemInfo() (SystemInfo, error) {
 	var sys SystemInfo
@@ -56,15 +58,48 @@ func GetSystemInfo() (SystemInfo, error) {
 	}
 
 	var osItems []win32_OperatingSystem
-	if e := wmi.Query("SELECT Caption FROM Win32_OperatingSystem", &osItems); e == nil && len(osItems) > 0 {
+	if e := wmi.Query("SELECT Caption, OSArchitecture FROM Win32_OperatingSystem", &osItems); e == nil && len(osItems) > 0 {
 		sys.OS = cleanWS(osItems[0].Caption)
+		sys.Architecture = cleanWS(osItems[0].OSArchitecture)
 	} else if e != nil {
 		err = wrapErr(err, e)
 	}
 
+	if e := readOSVersion(&sys); e != nil {
+		err = wrapErr(err, e)
+	}
+
 	return sys, err
 }
 
+// readOSVersion fills build, UBR, display version, edition and install date.
+func readOSVersion(sys *SystemInfo) error {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`, registry.QUERY_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+
+	if b, _, e := k.GetStringValue("CurrentBuildNumber"); e == nil {
+		sys.OSBuild = ParseBuildNumber(b)
+	}
+	if u, _, e := k.GetIntegerValue("UBR"); e == nil {
+		sys.OSUBR = int(u)
+	}
+	if dv, _, e := k.GetStringValue("DisplayVersion"); e == nil {
+		sys.DisplayVersion = dv
+	} else if rid, _, e := k.GetStringValue("ReleaseId"); e == nil {
+		sys.DisplayVersion = rid // Windows 10 before 20H2
+	}
+	if ed, _, e := k.GetStringValue("EditionID"); e == nil {
+		sys.Edition = ed
+	}
+	if d, _, e := k.GetIntegerValue("InstallDate"); e == nil && d > 0 {
+		sys.InstallDate = time.Unix(int64(d), 0)
+	}
+	return nil
+}
+
 func cleanWS(s string) string { return strings.TrimSpace(strings.ReplaceAll(s, "\n", " ")) }
 func wrapErr(base, newerr error) error {
 	if base == nil { return newerr }
@@ -0,0 +1,207 @@
+package system
+
+// Vanguard install integrity: hash every file under the install folder and
+// compare with a known-good manifest for the installed version.
+// Manifests are generated from a clean install with `vsc manifest`.
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed vanguard_manifests.json
+var bundledVanguardManifests []byte
+
+type VanguardManifests struct {
+	Ignore    []string           `json:"ignore"` // "Logs/" = folder, otherwise a glob on the path or file name
+	Manifests []VanguardManifest `json:"manifests"`
+}
+
+type VanguardManifest struct {
+	Version string            `json:"version"` // vgc.exe FileVersion
+	Files   map[string]string `json:"files"`   // slash-separated relative path -> sha256
+}
+
+// LoadVanguardManifests reads a manifest file; an empty path returns the bundled one.
+func LoadVanguardManifests(path string) (VanguardManifests, error) {
+	raw := bundledVanguardManifests
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return VanguardManifests{}, err
+		}
+		raw = b
+	}
+	var vm VanguardManifests
+	err := json.Unmarshal(raw, &vm)
+	return vm, err
+}
+
+func (vm VanguardManifests) For(version string) (VanguardManifest, bool) {
+	for _, m := range vm.Manifests {
+		if m.Version == version {
+			return m, true
+		}
+	}
+	return VanguardManifest{}, false
+}
+
+// CheckVanguardIntegrity compares installPath with the manifest for version
+// from manifestPath (bundled when empty). Without a manifest for that version
+// the report has Checked=false.
+func CheckVanguardIntegrity(installPath, version, manifestPath string) (IntegrityReport, error) {
+	rep := IntegrityReport{Version: version, ManifestSource: "bundled"}
+	if manifestPath != "" {
+		rep.ManifestSource = manifestPath
+	}
+	if installPath == "" || version == "" {
+		return rep, nil
+	}
+
+	vm, err := LoadVanguardManifests(manifestPath)
+	if err != nil {
+		return rep, err
+	}
+	m, ok := vm.For(version)
+	if !ok {
+		return rep, nil
+	}
+
+	files, err := hashTree(installPath, vm.Ignore)
+	rep.Checked = true
+	rep.Files = len(files)
+
+	// A file we cannot read is neither missing nor modified: compare the rest.
+	unreadable := map[string]bool{}
+	for p, h := range files {
+		if h == "" {
+			rep.Unreadable = append(rep.Unreadable, p)
+			unreadable[strings.ToLower(p)] = true
+			delete(files, p)
+		}
+	}
+	want := map[string]string{}
+	for p, h := range m.Files {
+		if !unreadable[strings.ToLower(p)] {
+			want[p] = h
+		}
+	}
+	sort.Strings(rep.Unreadable)
+	rep.Missing, rep.Extra, rep.Modified = CompareManifest(want, files)
+	return rep, err
+}
+
+// BuildVanguardManifest hashes a (known-good) install folder, skipping the
+// Ignore patterns of the manifest file at manifestPath (bundled when empty),
+// the same ones CheckVanguardIntegrity will apply.
+func BuildVanguardManifest(installPath, version, manifestPath string) (VanguardManifest, error) {
+	vm, err := LoadVanguardManifests(manifestPath)
+	if err != nil {
+		return VanguardManifest{}, err
+	}
+	files, err := hashTree(installPath, vm.Ignore)
+	return VanguardManifest{Version: version, Files: files}, err
+}
+
+// CompareManifest returns the sorted missing, extra and modified paths.
+func CompareManifest(want, got map[string]string) (missing, extra, modified []string) {
+	for p, h := range want {
+		g, ok := lookupFold(got, p)
+		switch {
+		case !ok:
+			missing = append(missing, p)
+		case !strings.EqualFold(g, h):
+			modified = append(modified, p)
+		}
+	}
+	for p := range got {
+		if _, ok := lookupFold(want, p); !ok {
+			extra = append(extra, p)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	sort.Strings(modified)
+	return missing, extra, modified
+}
+
+// hashTree hashes every file under root, keyed by slash-separated relative path.
+// Unreadable files get an empty hash.
+func hashTree(root string, ignore []string) (map[string]string, error) {
+	out := map[string]string{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if ignored(rel, ignore) {
+			return nil
+		}
+		out[rel] = hashFile(p)
+		return nil
+	})
+	return out, err
+}
+
+func ignored(rel string, patterns []string) bool {
+	low := strings.ToLower(rel)
+	for _, pat := range patterns {
+		pat = strings.ToLower(pat)
+		if strings.HasSuffix(pat, "/") {
+			if strings.HasPrefix(low, pat) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pat, low); ok {
+			return true
+		}
+		if ok, _ := path.Match(pat, path.Base(low)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupFold: Windows paths are case-insensitive.
+func lookupFold(m map[string]string, key string) (string, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// hashFile returns the hex SHA-256 of path, "" when it cannot be read.
+func hashFile(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,95 @@
+package system
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func sha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestCheckVanguardIntegrity(t *testing.T) {
+	install := t.TempDir()
+	for name, body := range map[string]string{
+		"vgc.exe":      "vgc",
+		"vgk.sys":      "patched vgk",
+		"extra.dll":    "dropped by something else",
+		"Logs/vgc.log": "ignored",
+	} {
+		p := filepath.Join(install, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file that exists but cannot be opened.
+	if err := os.Symlink(filepath.Join(install, "gone"), filepath.Join(install, "vgtray.exe")); err != nil {
+		t.Skip("symlinks not available:", err)
+	}
+
+	manifest := VanguardManifests{
+		Ignore: []string{"Logs/"},
+		Manifests: []VanguardManifest{{Version: "1.17.2.1", Files: map[string]string{
+			"vgc.exe":    sha256Hex("vgc"),
+			"vgk.sys":    sha256Hex("vgk"),
+			"vgtray.exe": sha256Hex("vgtray"),
+			"vgrl.dll":   sha256Hex("vgrl"),
+		}}},
+	}
+	mpath := filepath.Join(t.TempDir(), "manifest.json")
+	b, _ := json.Marshal(manifest)
+	if err := os.WriteFile(mpath, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := CheckVanguardIntegrity(install, "1.17.2.1", mpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rep.Checked || rep.Files != 4 {
+		t.Errorf("Checked=%v Files=%d", rep.Checked, rep.Files)
+	}
+	for name, got := range map[string][]string{"missing": rep.Missing, "modified": rep.Modified, "extra": rep.Extra, "unreadable": rep.Unreadable} {
+		want := map[string][]string{
+			"missing":    {"vgrl.dll"},
+			"modified":   {"vgk.sys"},
+			"extra":      {"extra.dll"},
+			"unreadable": {"vgtray.exe"},
+		}[name]
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+
+	// No manifest for this version: nothing is compared.
+	rep, err = CheckVanguardIntegrity(install, "9.9.9", mpath)
+	if err != nil || rep.Checked || len(rep.Modified) > 0 {
+		t.Errorf("unknown version: %+v, %v", rep, err)
+	}
+
+	// vsc manifest skips what the compared manifest file ignores, not the
+	// bundled list.
+	ipath := filepath.Join(t.TempDir(), "ignore.json")
+	if err := os.WriteFile(ipath, []byte(`{"ignore": ["*.dll"], "manifests": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := BuildVanguardManifest(install, "1.17.2.1", ipath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.Files["extra.dll"]; ok {
+		t.Errorf("ignored extra.dll is in the manifest")
+	}
+	if _, ok := m.Files["Logs/vgc.log"]; !ok || m.Version != "1.17.2.1" {
+		t.Errorf("manifest %+v", m)
+	}
+}
@@ -0,0 +1,378 @@
+package system
+
+// Kernel crash dump reader (C:\Windows\Minidump\*.dmp, MEMORY.DMP). The
+// 64-bit dump header ("PAGEDU64", two pages) holds the bugcheck code and
+// parameters; small (triage) dumps follow it with a TRIAGE_DUMP64 header that
+// points at the loaded driver list and the raw stack of the crashing thread.
+// From those we guess which driver caused the blue screen.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	dumpHeader64Size   = 0x2000
+	dumpTypeFull       = 1
+	dumpTypeKernel     = 2
+	dumpTypeTriage     = 4
+	dumpTypeBitmapFull = 5
+	dumpTypeBitmapKern = 6
+	dumpTriageValid    = 0x54524744 // "DGRT"
+	dumpDriverEntry64  = 0x90
+	dumpFillPattern    = 0x45474150 // "PAGE", unused header fields
+	machineAMD64       = 0x8664
+	maxMinidumps       = 5
+	maxStackModules    = 10
+)
+
+// Common bugcheck codes (the 0x10000000 bit of the *_M variants is ignored).
+var bugCheckNames = map[uint32]string{
+	0x0A:       "IRQL_NOT_LESS_OR_EQUAL",
+	0x19:       "BAD_POOL_HEADER",
+	0x1A:       "MEMORY_MANAGEMENT",
+	0x1E:       "KMODE_EXCEPTION_NOT_HANDLED",
+	0x3B:       "SYSTEM_SERVICE_EXCEPTION",
+	0x4E:       "PFN_LIST_CORRUPT",
+	0x50:       "PAGE_FAULT_IN_NONPAGED_AREA",
+	0x77:       "KERNEL_STACK_INPAGE_ERROR",
+	0x7A:       "KERNEL_DATA_INPAGE_ERROR",
+	0x7E:       "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED",
+	0x7F:       "UNEXPECTED_KERNEL_MODE_TRAP",
+	0x8E:       "KERNEL_MODE_EXCEPTION_NOT_HANDLED",
+	0x9C:       "MACHINE_CHECK_EXCEPTION",
+	0x9F:       "DRIVER_POWER_STATE_FAILURE",
+	0xBE:       "ATTEMPTED_WRITE_TO_READONLY_MEMORY",
+	0xC2:       "BAD_POOL_CALLER",
+	0xC4:       "DRIVER_VERIFIER_DETECTED_VIOLATION",
+	0xC5:       "DRIVER_CORRUPTED_EXPOOL",
+	0xD1:       "DRIVER_IRQL_NOT_LESS_OR_EQUAL",
+	0xEF:       "CRITICAL_PROCESS_DIED",
+	0xF7:       "DRIVER_OVERRAN_STACK_BUFFER",
+	0xFC:       "ATTEMPTED_EXECUTE_OF_NOEXECUTE_MEMORY",
+	0x101:      "CLOCK_WATCHDOG_TIMEOUT",
+	0x109:      "CRITICAL_STRUCTURE_CORRUPTION",
+	0x113:      "VIDEO_DXGKRNL_FATAL_ERROR",
+	0x116:      "VIDEO_TDR_FAILURE",
+	0x117:      "VIDEO_TDR_TIMEOUT_DETECTED",
+	0x119:      "VIDEO_SCHEDULER_INTERNAL_ERROR",
+	0x124:      "WHEA_UNCORRECTABLE_ERROR",
+	0x133:      "DPC_WATCHDOG_VIOLATION",
+	0x139:      "KERNEL_SECURITY_CHECK_FAILURE",
+	0x13A:      "KERNEL_MODE_HEAP_CORRUPTION",
+	0x154:      "UNEXPECTED_STORE_EXCEPTION",
+	0xC000021A: "STATUS_SYSTEM_PROCESS_TERMINATED",
+}
+
+// Bugcheck parameter (1-based) holding the faulting instruction address.
+var bugCheckFaultParam = map[uint32]int{
+	0x0A: 4,
+	0x1E: 2,
+	0x3B: 2,
+	0x50: 3,
+	0x7E: 2,
+	0x8E: 2,
+	0xD1: 4,
+}
+
+// Hardware errors: the stack points at whatever was running, not a culprit.
+var hardwareBugChecks = map[uint32]bool{0x9C: true, 0x101: true, 0x124: true}
+
+// Kernel and HAL images; a fault there is usually caused by a driver below.
+var coreKernelModules = []string{"ntoskrnl.exe", "ntkrnlmp.exe", "ntkrnlpa.exe", "ntkrpamp.exe", "hal.dll", "halmacpi.dll"}
+
+var errNotKernelDump = errors.New("not a Windows kernel crash dump")
+
+// ScanMinidumps analyzes the newest small memory dumps in dir (default
+// %SystemRoot%\Minidump). Reading them needs an elevated prompt.
+func ScanMinidumps(dir string) (MinidumpScan, error) {
+	if dir == "" {
+		root := os.Getenv("SystemRoot")
+		if root == "" {
+			root = `C:\Windows`
+		}
+		dir = filepath.Join(root, "Minidump")
+	}
+	scan := MinidumpScan{Dir: dir}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return scan, nil // never crashed (or small dumps disabled)
+		}
+		return scan, err
+	}
+
+	type dumpFile struct {
+		path string
+		mod  time.Time
+	}
+	var files []dumpFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".dmp") {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			files = append(files, dumpFile{filepath.Join(dir, e.Name()), info.ModTime()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.After(files[j].mod) })
+
+	var errs []error
+	for _, f := range files[:min(len(files), maxMinidumps)] {
+		d, err := ReadCrashDump(f.path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(f.path), err))
+			continue
+		}
+		if d.Time.IsZero() {
+			d.Time = f.mod
+		}
+		scan.Dumps = append(scan.Dumps, d)
+	}
+	return scan, errors.Join(errs...)
+}
+
+// ReadCrashDump analyzes one kernel dump file.
+func ReadCrashDump(path string) (CrashDump, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return CrashDump{}, err
+	}
+	defer f.Close()
+
+	d, err := ParseCrashDump(f)
+	d.File = path
+	return d, err
+}
+
+// ParseCrashDump reads the dump header and, for small dumps, the driver list
+// and stack. Full and kernel dumps only yield the bugcheck.
+func ParseCrashDump(r io.ReaderAt) (CrashDump, error) {
+	h := make([]byte, dumpHeader64Size)
+	if _, err := r.ReadAt(h, 0); err != nil {
+		return CrashDump{}, err
+	}
+	switch string(h[:8]) {
+	case "PAGEDU64":
+	case "PAGEDUMP":
+		return CrashDump{}, errors.New("32-bit kernel dumps are not supported")
+	default:
+		if string(h[:4]) == "MDMP" {
+			return CrashDump{}, errors.New("user-mode minidump, not a blue screen dump")
+		}
+		return CrashDump{}, errNotKernelDump
+	}
+
+	le := binary.LittleEndian
+	d := CrashDump{
+		BuildNumber:  le.Uint32(h[0x0C:]),
+		BugCheckCode: le.Uint32(h[0x38:]),
+		Time:         fileTime(le.Uint64(h[0xFA8:])),
+	}
+	for i := range d.BugCheckParams {
+		d.BugCheckParams[i] = le.Uint64(h[0x40+8*i:])
+	}
+	d.BugCheckName = BugCheckName(d.BugCheckCode)
+
+	dumpType := le.Uint32(h[0xF98:])
+	switch dumpType {
+	case dumpTypeTriage:
+		d.DumpType = "minidump"
+	case dumpTypeFull, dumpTypeBitmapFull:
+		d.DumpType = "complete"
+	case dumpTypeKernel, dumpTypeBitmapKern:
+		d.DumpType = "kernel"
+	default:
+		d.DumpType = fmt.Sprintf("type %d", dumpType)
+	}
+
+	var candidates []uint64
+	if p, ok := bugCheckFaultParam[d.BugCheckCode&^0x10000000]; ok {
+		candidates = append(candidates, d.BugCheckParams[p-1])
+	}
+	if code := le.Uint32(h[0xF00:]); code != 0 && code != dumpFillPattern {
+		candidates = append(candidates, le.Uint64(h[0xF10:])) // ExceptionAddress
+	}
+
+	if dumpType != dumpTypeTriage {
+		d.Note = "only small memory dumps (Minidump folder) carry the driver list"
+		return d, nil
+	}
+	t, err := readTriage(r, le.Uint32(h[0x30:]))
+	if err != nil {
+		return d, err
+	}
+	d.Modules = t.modules
+	if t.rip != 0 {
+		candidates = append(candidates, t.rip)
+	}
+	for _, a := range candidates {
+		if a != 0 {
+			d.FaultAddress = a
+			break
+		}
+	}
+	d.FaultModule = d.moduleAt(d.FaultAddress)
+	d.StackModules = d.stackModules(t.stack)
+	d.attribute()
+	return d, nil
+}
+
+// BugCheckName returns the symbolic name of a bugcheck code, or its hex form.
+func BugCheckName(code uint32) string {
+	if n, ok := bugCheckNames[code&^0x10000000]; ok {
+		return n
+	}
+	return fmt.Sprintf("0x%08X", code)
+}
+
+type triageDump struct {
+	modules []DumpModule
+	stack   []uint64
+	rip     uint64
+}
+
+func readTriage(r io.ReaderAt, machine uint32) (triageDump, error) {
+	var t triageDump
+	th := make([]byte, 0x50)
+	if _, err := r.ReadAt(th, dumpHeader64Size); err != nil {
+		return t, fmt.Errorf("triage header: %w", err)
+	}
+	le := binary.LittleEndian
+	if valid := int64(le.Uint32(th[0x08:])); valid != 0 {
+		var v [4]byte
+		if _, err := r.ReadAt(v[:], valid); err != nil || le.Uint32(v[:]) != dumpTriageValid {
+			return t, errors.New("triage dump not marked valid")
+		}
+	}
+
+	if ctx := int64(le.Uint32(th[0x0C:])); ctx != 0 && machine == machineAMD64 {
+		var rip [8]byte
+		if _, err := r.ReadAt(rip[:], ctx+0xF8); err == nil {
+			t.rip = le.Uint64(rip[:])
+		}
+	}
+
+	list := int64(le.Uint32(th[0x30:]))
+	count := int(le.Uint32(th[0x34:]))
+	if count > 4096 {
+		return t, fmt.Errorf("implausible driver count %d", count)
+	}
+	if count > 0 {
+		entries := make([]byte, count*dumpDriverEntry64)
+		if _, err := r.ReadAt(entries, list); err != nil {
+			return t, fmt.Errorf("driver list: %w", err)
+		}
+		for i := 0; i < count; i++ {
+			e := entries[i*dumpDriverEntry64:]
+			m := DumpModule{
+				Base: le.Uint64(e[8+0x30:]),
+				Size: le.Uint32(e[8+0x40:]),
+			}
+			if ts := le.Uint32(e[8+0x80:]); ts != 0 {
+				m.TimeStamp = time.Unix(int64(ts), 0).UTC()
+			}
+			m.Name = readDumpString(r, int64(le.Uint32(e)))
+			t.modules = append(t.modules, m)
+		}
+	}
+
+	if off, size := int64(le.Uint32(th[0x28:])), int(le.Uint32(th[0x2C:])); off != 0 && size > 0 && size <= 1<<20 {
+		raw := make([]byte, size)
+		if _, err := r.ReadAt(raw, off); err == nil {
+			for i := 0; i+8 <= len(raw); i += 8 {
+				t.stack = append(t.stack, le.Uint64(raw[i:]))
+			}
+		}
+	}
+	return t, nil
+}
+
+// readDumpString reads a DUMP_STRING (character count, UTF-16 text).
+func readDumpString(r io.ReaderAt, off int64) string {
+	var n [4]byte
+	if off == 0 {
+		return ""
+	}
+	if _, err := r.ReadAt(n[:], off); err != nil {
+		return ""
+	}
+	chars := int(binary.LittleEndian.Uint32(n[:]))
+	if chars <= 0 || chars > 1024 {
+		return ""
+	}
+	b := make([]byte, 2*chars)
+	if _, err := r.ReadAt(b, off+4); err != nil {
+		return ""
+	}
+	u := make([]uint16, chars)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
+
+func (d CrashDump) moduleAt(addr uint64) string {
+	if addr == 0 {
+		return ""
+	}
+	for _, m := range d.Modules {
+		if addr >= m.Base && addr < m.Base+uint64(m.Size) {
+			return m.Name
+		}
+	}
+	return ""
+}
+
+// stackModules lists the modules that stack values point into, innermost
+// first. Not every hit is a return address, but drivers on the faulting path
+// show up here.
+func (d CrashDump) stackModules(stack []uint64) []string {
+	var out []string
+	for _, v := range stack {
+		if name := d.moduleAt(v); name != "" {
+			out = appendUnique(out, name)
+			if len(out) == maxStackModules {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// attribute picks the likely culprit: the faulting module unless it is the
+// kernel itself, then the first other driver found on the stack.
+func (d *CrashDump) attribute() {
+	if hardwareBugChecks[d.BugCheckCode&^0x10000000] {
+		d.Note = "hardware error reported by the CPU or firmware, not a driver fault"
+		return
+	}
+	d.Culprit = d.FaultModule
+	if d.Culprit == "" || isCoreKernelModule(d.Culprit) {
+		for _, m := range d.StackModules {
+			if !isCoreKernelModule(m) {
+				d.Culprit = m
+				break
+			}
+		}
+	}
+	d.VanguardInvolved = strings.EqualFold(d.Culprit, "vgk.sys")
+}
+
+func isCoreKernelModule(name string) bool {
+	for _, m := range coreKernelModules {
+		if strings.EqualFold(name, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package system
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+const (
+	kernelBase = 0xFFFFF80000000000
+	vgkBase    = 0xFFFFF80010000000
+	nvBase     = 0xFFFFF80020000000
+)
+
+// synthTriageDump builds a 64-bit small memory dump: the PAGEDU64 header and
+// a triage header pointing at the context (RIP), the raw stack and the driver
+// list of ntoskrnl.exe, vgk.sys and nvlddmkm.sys.
+func synthTriageDump(code uint32, params [4]uint64, rip uint64, stack []uint64) []byte {
+	const (
+		ctxOff     = 0x3000
+		listOff    = 0x4000
+		stringsOff = 0x5000
+		stackOff   = 0x6000
+		validOff   = 0x7000
+	)
+	le := binary.LittleEndian
+	d := make([]byte, 0x8000)
+	copy(d, "PAGEDU64")
+	le.PutUint32(d[0x0C:], 22631)
+	le.PutUint32(d[0x30:], machineAMD64)
+	le.PutUint32(d[0x38:], code)
+	for i, p := range params {
+		le.PutUint64(d[0x40+8*i:], p)
+	}
+	le.PutUint32(d[0xF00:], dumpFillPattern)
+	le.PutUint32(d[0xF98:], dumpTypeTriage)
+	le.PutUint64(d[0xFA8:], 133700000000000000)
+
+	th := d[dumpHeader64Size:]
+	le.PutUint32(th[0x08:], validOff)
+	le.PutUint32(th[0x0C:], ctxOff)
+	le.PutUint32(th[0x28:], stackOff)
+	le.PutUint32(th[0x2C:], uint32(8*len(stack)))
+	le.PutUint32(th[0x30:], listOff)
+	le.PutUint32(th[0x34:], 3)
+	le.PutUint32(d[validOff:], dumpTriageValid)
+	le.PutUint64(d[ctxOff+0xF8:], rip)
+	for i, v := range stack {
+		le.PutUint64(d[stackOff+8*i:], v)
+	}
+
+	str := stringsOff
+	for i, m := range []struct {
+		name string
+		base uint64
+		size uint32
+	}{
+		{"ntoskrnl.exe", kernelBase, 0x1000000},
+		{"vgk.sys", vgkBase, 0x100000},
+		{"nvlddmkm.sys", nvBase, 0x2000000},
+	} {
+		e := d[listOff+i*dumpDriverEntry64:]
+		le.PutUint32(e, uint32(str))
+		le.PutUint64(e[8+0x30:], m.base)
+		le.PutUint32(e[8+0x40:], m.size)
+		le.PutUint32(e[8+0x80:], 0x65000000)
+		u := utf16.Encode([]rune(m.name))
+		le.PutUint32(d[str:], uint32(len(u)))
+		for j, c := range u {
+			le.PutUint16(d[str+4+2*j:], c)
+		}
+		str += 4 + 2*len(u) + 2
+	}
+	return d
+}
+
+func TestParseCrashDump(t *testing.T) {
+	tests := []struct {
+		name               string
+		code               uint32
+		params             [4]uint64
+		rip                uint64
+		stack              []uint64
+		wantName, culprit  string
+		fault              string
+		stackMods          []string
+		vanguard, hardware bool
+	}{
+		{
+			name:      "fault address parameter in vgk",
+			code:      0x3B,
+			params:    [4]uint64{0xC0000005, vgkBase + 0x1234, 0, 0},
+			rip:       kernelBase + 0x10,
+			stack:     []uint64{0x1, kernelBase + 0x400, vgkBase + 0x1300},
+			wantName:  "SYSTEM_SERVICE_EXCEPTION",
+			fault:     "vgk.sys",
+			culprit:   "vgk.sys",
+			stackMods: []string{"ntoskrnl.exe", "vgk.sys"},
+			vanguard:  true,
+		},
+		{
+			name:      "_M variant faulting in the kernel",
+			code:      0x1000007E,
+			params:    [4]uint64{0xC0000005, kernelBase + 0x800, 0, 0},
+			stack:     []uint64{kernelBase + 0x900, 0, nvBase + 0x55, kernelBase + 0x900, vgkBase},
+			wantName:  "SYSTEM_THREAD_EXCEPTION_NOT_HANDLED",
+			fault:     "ntoskrnl.exe",
+			culprit:   "nvlddmkm.sys",
+			stackMods: []string{"ntoskrnl.exe", "nvlddmkm.sys", "vgk.sys"},
+		},
+		{
+			name:      "RIP from the context when no parameter names the address",
+			code:      0x139,
+			rip:       nvBase + 0x10,
+			wantName:  "KERNEL_SECURITY_CHECK_FAILURE",
+			fault:     "nvlddmkm.sys",
+			culprit:   "nvlddmkm.sys",
+			stackMods: nil,
+		},
+		{
+			name:      "WHEA",
+			code:      0x124,
+			rip:       vgkBase + 0x10,
+			stack:     []uint64{vgkBase + 0x20},
+			wantName:  "WHEA_UNCORRECTABLE_ERROR",
+			fault:     "vgk.sys",
+			stackMods: []string{"vgk.sys"},
+			hardware:  true,
+		},
+		{
+			name:      "machine check, _M variant",
+			code:      0x1000009C,
+			rip:       vgkBase + 0x10,
+			wantName:  "MACHINE_CHECK_EXCEPTION",
+			fault:     "vgk.sys",
+			stackMods: nil,
+			hardware:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseCrashDump(bytes.NewReader(synthTriageDump(tt.code, tt.params, tt.rip, tt.stack)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.DumpType != "minidump" || d.BuildNumber != 22631 || d.Time.IsZero() || len(d.Modules) != 3 {
+				t.Errorf("header: %+v", d)
+			}
+			if d.BugCheckName != tt.wantName || d.FaultModule != tt.fault || d.Culprit != tt.culprit ||
+				d.VanguardInvolved != tt.vanguard || !reflect.DeepEqual(d.StackModules, tt.stackMods) {
+				t.Errorf("got name %s fault %q culprit %q vanguard %v stack %v",
+					d.BugCheckName, d.FaultModule, d.Culprit, d.VanguardInvolved, d.StackModules)
+			}
+			if hw := d.Note != ""; hw != tt.hardware {
+				t.Errorf("note = %q, hardware error %v", d.Note, tt.hardware)
+			}
+		})
+	}
+}
+
+func TestParseCrashDumpModules(t *testing.T) {
+	d, err := ParseCrashDump(bytes.NewReader(synthTriageDump(0x50, [4]uint64{}, 0, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := d.Modules[1]
+	if m.Name != "vgk.sys" || m.Base != vgkBase || m.Size != 0x100000 || m.TimeStamp.Unix() != 0x65000000 {
+		t.Errorf("module: %+v", m)
+	}
+	if d.FaultAddress != 0 || d.Culprit != "" {
+		t.Errorf("no fault address: %+v", d)
+	}
+}
+
+func TestParseCrashDumpErrors(t *testing.T) {
+	kernel := synthTriageDump(0xD1, [4]uint64{0, 2, 0, vgkBase}, 0, nil)[:dumpHeader64Size]
+	binary.LittleEndian.PutUint32(kernel[0xF98:], dumpTypeKernel)
+	d, err := ParseCrashDump(bytes.NewReader(kernel))
+	if err != nil || d.DumpType != "kernel" || d.BugCheckName != "DRIVER_IRQL_NOT_LESS_OR_EQUAL" || d.Note == "" || d.Modules != nil {
+		t.Errorf("kernel dump: %+v, %v", d, err)
+	}
+
+	invalid := synthTriageDump(0x3B, [4]uint64{}, 0, nil)
+	copy(invalid[0x7000:], "XXXX")
+
+	for name, data := range map[string][]byte{
+		"invalid triage": invalid,
+		"truncated":      synthTriageDump(0x3B, [4]uint64{}, 0, nil)[:0x3000],
+		"user-mode":      append([]byte("MDMP"), make([]byte, dumpHeader64Size)...),
+		"32-bit":         append([]byte("PAGEDUMP"), make([]byte, dumpHeader64Size)...),
+		"short":          []byte("PAGEDU64"),
+	} {
+		if _, err := ParseCrashDump(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+	if _, err := ParseCrashDump(bytes.NewReader(make([]byte, dumpHeader64Size))); !errors.Is(err, errNotKernelDump) {
+		t.Errorf("zeros: err = %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package system
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseBuildNumber reads the CurrentBuildNumber registry string, 0 when it is
+// not a number.
+func ParseBuildNumber(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// BuildAtLeast reports whether Windows build.ubr is minBuild.minUBR or newer.
+// An unknown build (0) passes so that an unreadable registry does not block;
+// a missing UBR counts as 0, the release build.
+func BuildAtLeast(build, ubr, minBuild, minUBR int) bool {
+	if build == 0 {
+		return true
+	}
+	if build != minBuild {
+		return build > minBuild
+	}
+	return ubr >= minUBR
+}
@@ -0,0 +1,36 @@
+package system
+
+import "testing"
+
+func TestBuildAtLeast(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		build, ubr int
+		want       bool
+	}{
+		{"at the minimum", 19045, 3803, true},
+		{"newer update of the minimum build", 19045, 6456, true},
+		{"older update of the minimum build", 19045, 2006, false},
+		{"minimum build, UBR missing", 19045, 0, false},
+		{"older build", 19044, 9999, false},
+		{"newer build", 22631, 0, true},
+		{"newer build, older UBR", 26100, 1, true},
+		{"unknown build", 0, 0, true},
+	} {
+		if got := BuildAtLeast(tc.build, tc.ubr, 19045, 3803); got != tc.want {
+			t.Errorf("%s: BuildAtLeast(%d, %d) = %v, want %v", tc.name, tc.build, tc.ubr, got, tc.want)
+		}
+	}
+	// Without a minimum UBR any update of the build passes, including none.
+	if !BuildAtLeast(19045, 0, 19045, 0) {
+		t.Error("19045 without UBR rejected with no minimum UBR")
+	}
+}
+
+func TestParseBuildNumber(t *testing.T) {
+	for in, want := range map[string]int{"19045": 19045, " 22631 ": 22631, "": 0, "abc": 0, "-1": 0} {
+		if got := ParseBuildNumber(in); got != want {
+			t.Errorf("ParseBuildNumber(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package system
+
+// VS_VERSIONINFO reader: walks the PE resource tree to RT_VERSION and decodes
+// VS_FIXEDFILEINFO and the first StringFileInfo table. Works on any PE
+// (exe, sys, dll) without Windows APIs.
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	peDirResource    = 2
+	rtVersion        = 16
+	vsFixedSignature = 0xFEEF04BD
+)
+
+var errNoVersionInfo = errors.New("no version resource")
+
+// ReadVersionInfo reads the version resource of a PE file. Only the headers
+// and the resource section are read, so large game binaries are fine.
+func ReadVersionInfo(path string) (PEVersionInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return PEVersionInfo{}, err
+	}
+	defer f.Close()
+	return versionInfoFrom(f)
+}
+
+func ParseVersionInfo(data []byte) (PEVersionInfo, error) {
+	return versionInfoFrom(bytes.NewReader(data))
+}
+
+func versionInfoFrom(r io.ReaderAt) (PEVersionInfo, error) {
+	f, err := pe.NewFile(r)
+	if err != nil {
+		return PEVersionInfo{}, err
+	}
+	defer f.Close()
+
+	var dir pe.DataDirectory
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		if oh.NumberOfRvaAndSizes > peDirResource {
+			dir = oh.DataDirectory[peDirResource]
+		}
+	case *pe.OptionalHeader64:
+		if oh.NumberOfRvaAndSizes > peDirResource {
+			dir = oh.DataDirectory[peDirResource]
+		}
+	}
+	if dir.VirtualAddress == 0 || dir.Size == 0 {
+		return PEVersionInfo{}, errNoVersionInfo
+	}
+
+	rsrc, base := sectionBytes(f, dir.VirtualAddress)
+	if rsrc == nil {
+		return PEVersionInfo{}, errors.New("resource directory outside the sections")
+	}
+
+	// type -> name -> language; take the first name and language.
+	off, ok := resourceEntry(rsrc, 0, rtVersion)
+	for level := 0; ok && level < 2; level++ {
+		off, ok = resourceEntry(rsrc, off, -1)
+	}
+	if !ok || off+16 > len(rsrc) {
+		return PEVersionInfo{}, errNoVersionInfo
+	}
+
+	rva := binary.LittleEndian.Uint32(rsrc[off:])
+	size := int(binary.LittleEndian.Uint32(rsrc[off+4:]))
+	start := int(rva) - int(base)
+	if start < 0 || start+size > len(rsrc) {
+		return PEVersionInfo{}, errors.New("version resource outside the section")
+	}
+	return parseVSVersionInfo(rsrc[start : start+size])
+}
+
+// sectionBytes returns the raw data of the section holding rva, from rva on.
+func sectionBytes(f *pe.File, rva uint32) ([]byte, uint32) {
+	for _, s := range f.Sections {
+		if rva < s.VirtualAddress || rva >= s.VirtualAddress+max(s.VirtualSize, s.Size) {
+			continue
+		}
+		data, err := s.Data()
+		skip := int(rva - s.VirtualAddress)
+		if err != nil || skip >= len(data) {
+			return nil, 0
+		}
+		return data[skip:], rva
+	}
+	return nil, 0
+}
+
+// resourceEntry finds the entry with the given ID (-1: the first entry) in the
+// IMAGE_RESOURCE_DIRECTORY at off and returns the offset it points to.
+func resourceEntry(rsrc []byte, off int, id int) (int, bool) {
+	if off < 0 || off+16 > len(rsrc) {
+		return 0, false
+	}
+	n := int(binary.LittleEndian.Uint16(rsrc[off+12:])) + int(binary.LittleEndian.Uint16(rsrc[off+14:]))
+	for i := 0; i < n; i++ {
+		e := off + 16 + i*8
+		if e+8 > len(rsrc) {
+			return 0, false
+		}
+		name := binary.LittleEndian.Uint32(rsrc[e:])
+		target := binary.LittleEndian.Uint32(rsrc[e+4:])
+		if id >= 0 && (name&0x80000000 != 0 || int(name) != id) {
+			continue
+		}
+		return int(target &^ 0x80000000), true
+	}
+	return 0, false
+}
+
+// verBlock is one node of the VS_VERSIONINFO tree.
+type verBlock struct {
+	key      string
+	value    []byte
+	text     bool // wType 1: value is a UTF-16 string
+	children []byte
+}
+
+func parseVerBlock(b []byte) (verBlock, int, error) {
+	if len(b) < 6 {
+		return verBlock{}, 0, errors.New("truncated version block")
+	}
+	length := int(binary.LittleEndian.Uint16(b))
+	valLen := int(binary.LittleEndian.Uint16(b[2:]))
+	typ := binary.LittleEndian.Uint16(b[4:])
+	if length < 6 || length > len(b) {
+		return verBlock{}, 0, errors.New("bad version block length")
+	}
+	b = b[:length]
+
+	key, n := utf16z(b[6:])
+	if n < 0 {
+		return verBlock{}, 0, errors.New("unterminated version key")
+	}
+	off := align4(6 + n)
+
+	vb := verBlock{key: key, text: typ == 1}
+	if typ == 1 {
+		valLen *= 2 // in WORDs for text values
+	}
+	if off+valLen > length {
+		valLen = max(length-off, 0)
+	}
+	vb.value = b[min(off, length) : min(off, length)+valLen]
+	if c := align4(off + valLen); c < length {
+		vb.children = b[c:]
+	}
+	return vb, align4(length), nil
+}
+
+func eachVerBlock(b []byte, fn func(verBlock)) {
+	for len(b) >= 6 {
+		vb, n, err := parseVerBlock(b)
+		if err != nil {
+			return
+		}
+		fn(vb)
+		if n >= len(b) {
+			return
+		}
+		b = b[n:]
+	}
+}
+
+func parseVSVersionInfo(b []byte) (PEVersionInfo, error) {
+	root, _, err := parseVerBlock(b)
+	if err != nil {
+		return PEVersionInfo{}, err
+	}
+	if root.key != "VS_VERSION_INFO" {
+		return PEVersionInfo{}, fmt.Errorf("unexpected version key %q", root.key)
+	}
+
+	var vi PEVersionInfo
+	if v := root.value; len(v) >= 24 && binary.LittleEndian.Uint32(v) == vsFixedSignature {
+		vi.FixedFileVersion = fixedVersion(binary.LittleEndian.Uint32(v[8:]), binary.LittleEndian.Uint32(v[12:]))
+		vi.FixedProductVersion = fixedVersion(binary.LittleEndian.Uint32(v[16:]), binary.LittleEndian.Uint32(v[20:]))
+	}
+
+	strs := map[string]string{}
+	eachVerBlock(root.children, func(sfi verBlock) {
+		if sfi.key != "StringFileInfo" {
+			return
+		}
+		eachVerBlock(sfi.children, func(table verBlock) {
+			if len(strs) > 0 {
+				return // first language only
+			}
+			eachVerBlock(table.children, func(s verBlock) {
+				if v, _ := utf16z(s.value); v != "" || s.text {
+					strs[s.key] = v
+				}
+			})
+		})
+	})
+
+	vi.FileVersion = strs["FileVersion"]
+	vi.ProductVersion = strs["ProductVersion"]
+	vi.CompanyName = strs["CompanyName"]
+	vi.FileDescription = strs["FileDescription"]
+	vi.ProductName = strs["ProductName"]
+	vi.OriginalFilename = strs["OriginalFilename"]
+	if vi.FileVersion == "" {
+		vi.FileVersion = vi.FixedFileVersion
+	}
+	if vi.ProductVersion == "" {
+		vi.ProductVersion = vi.FixedProductVersion
+	}
+	return vi, nil
+}
+
+func fixedVersion(ms, ls uint32) string {
+	return fmt.Sprintf("%d.%d.%d.%d", ms>>16, ms&0xffff, ls>>16, ls&0xffff)
+}
+
+func align4(n int) int { return (n + 3) &^ 3 }
@@ -0,0 +1,50 @@
+package system
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadVersionInfo(t *testing.T) {
+	vi, err := ReadVersionInfo(filepath.Join("testdata", "pe", "versioninfo.exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PEVersionInfo{
+		FileVersion:         "0.1.0",
+		ProductVersion:      "0.1.0",
+		CompanyName:         "https://sindresorhus.com",
+		FileDescription:     "Get the terminal window size",
+		ProductName:         "term-size",
+		OriginalFilename:    "term-size.exe",
+		FixedFileVersion:    "0.1.0.0",
+		FixedProductVersion: "0.1.0.0",
+	}
+	if vi != want {
+		t.Fatalf("got  %+v\nwant %+v", vi, want)
+	}
+}
+
+func TestParseVersionInfoNoResource(t *testing.T) {
+	if _, err := ParseVersionInfo(readPEFixture(t, "signed.exe")); !errors.Is(err, errNoVersionInfo) {
+		t.Fatalf("err = %v, want %v", err, errNoVersionInfo)
+	}
+}
+
+func TestParseVersionInfoTruncated(t *testing.T) {
+	data := readPEFixture(t, "versioninfo.exe")
+	for _, n := range []int{0, 0x40, 0x200, len(data) / 2, len(data) - 0x200} {
+		if _, err := ParseVersionInfo(data[:n]); err == nil {
+			t.Errorf("truncated to %d bytes: no error", n)
+		}
+	}
+}
+
+// Every prefix of the file must fail cleanly, never panic.
+func TestParseVersionInfoPrefixes(t *testing.T) {
+	data := readPEFixture(t, "versioninfo.exe")
+	for n := 0; n < len(data); n += 7 {
+		ParseVersionInfo(data[:n])
+	}
+}
@@ -0,0 +1,132 @@
+package system
+
+// Riot Client / Valorant install discovery from ProgramData\Riot Games:
+//   RiotClientInstalls.json                              -> Riot Client path
+//   Metadata\valorant.<patchline>\valorant.<patchline>.product_settings.yaml -> game path
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type riotClientInstalls struct {
+	RCDefault        string            `json:"rc_default"`
+	RCLive           string            `json:"rc_live"`
+	AssociatedClient map[string]string `json:"associated_client"` // game folder -> client exe
+}
+
+// Relative to the Valorant install folder.
+const valorantShippingExe = `ShooterGame/Binaries/Win64/VALORANT-Win64-Shipping.exe`
+
+// GetRiotInstallInfo discovers the Riot Client and the Valorant installs.
+// programData defaults to %ProgramData% (C:\ProgramData).
+func GetRiotInstallInfo(programData string) (RiotInstallInfo, error) {
+	if programData == "" {
+		programData = os.Getenv("ProgramData")
+	}
+	if programData == "" {
+		programData = `C:\ProgramData`
+	}
+	riot := filepath.Join(programData, "Riot Games")
+	ri := RiotInstallInfo{ProgramData: programData}
+
+	var firstErr error
+	if b, err := os.ReadFile(filepath.Join(riot, "RiotClientInstalls.json")); err == nil {
+		var rc riotClientInstalls
+		if err := json.Unmarshal(b, &rc); err != nil {
+			firstErr = err
+		}
+		ri.ClientPath = firstNonEmpty(rc.RCLive, rc.RCDefault)
+		if ri.ClientPath == "" {
+			for _, c := range rc.AssociatedClient {
+				ri.ClientPath = c
+				break
+			}
+		}
+		ri.ClientPath = filepath.FromSlash(ri.ClientPath)
+	} else {
+		firstErr = err
+	}
+	if ri.ClientPath != "" {
+		if vi, err := ReadVersionInfo(ri.ClientPath); err == nil {
+			ri.ClientVersion = vi.FileVersion
+		}
+	}
+
+	dirs, _ := filepath.Glob(filepath.Join(riot, "Metadata", "valorant.*"))
+	sort.SliceStable(dirs, func(i, j int) bool { // live first
+		return strings.HasSuffix(dirs[i], ".live") && !strings.HasSuffix(dirs[j], ".live")
+	})
+	for _, d := range dirs {
+		patchline := strings.TrimPrefix(filepath.Base(d), "valorant.")
+		f, err := os.Open(filepath.Join(d, "valorant."+patchline+".product_settings.yaml"))
+		if err != nil {
+			continue
+		}
+		settings := ParseFlatYAML(f)
+		f.Close()
+
+		vi := ValorantInstall{
+			Patchline:    patchline,
+			Path:         filepath.FromSlash(settings["product_install_full_path"]),
+			ShouldRepair: settings["should_repair"] == "true",
+			Version:      settings["version"],
+		}
+		if vi.Path != "" {
+			if pv, err := ReadVersionInfo(filepath.Join(vi.Path, filepath.FromSlash(valorantShippingExe))); err == nil && vi.Version == "" {
+				vi.Version = pv.FileVersion
+			}
+			vi.Installed = isDir(vi.Path)
+			if free, err := volumeFreeBytes(vi.Path); err == nil {
+				vi.FreeBytes = free
+			}
+		}
+		ri.Valorant = append(ri.Valorant, vi)
+	}
+	if len(ri.Valorant) > 0 {
+		firstErr = nil // the client file is optional once the game is found
+	}
+	return ri, firstErr
+}
+
+// ParseFlatYAML reads the top-level "key: value" pairs of a simple YAML file
+// (Riot product settings), unquoting values. Nested blocks and lists are ignored.
+func ParseFlatYAML(r io.Reader) map[string]string {
+	out := map[string]string{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' || line[0] == '-' {
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+		out[strings.TrimSpace(k)] = v
+	}
+	return out
+}
+
+func isDir(p string) bool {
+	st, err := os.Stat(p)
+	return err == nil && st.IsDir()
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetRiotInstallInfo(t *testing.T) {
+	programData := t.TempDir()
+	riot := filepath.Join(programData, "Riot Games")
+	client := filepath.Join(t.TempDir(), "RiotClientServices.exe")
+	game := t.TempDir()
+
+	write := func(p, body string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(client, string(readPEFixture(t, "versioninfo.exe")))
+	write(filepath.Join(riot, "RiotClientInstalls.json"),
+		`{"associated_client":{"`+filepath.ToSlash(game)+`/":"`+filepath.ToSlash(client)+`"},"rc_default":"`+filepath.ToSlash(client)+`"}`)
+
+	// Written by the Riot Client on Windows: CRLF, mixed quoting, nested blocks.
+	live := strings.Join([]string{
+		"product_install_full_path: \"" + filepath.ToSlash(game) + "\"",
+		"product_install_root: '" + filepath.ToSlash(filepath.Dir(game)) + "'",
+		"settings:",
+		"  create_shortcut: true",
+		"  locale: \"en_US\"",
+		"should_repair: true",
+		"version: 10.04.00.2647584",
+		"",
+	}, "\r\n")
+	write(filepath.Join(riot, "Metadata", "valorant.live", "valorant.live.product_settings.yaml"), live)
+	write(filepath.Join(riot, "Metadata", "valorant.pbe", "valorant.pbe.product_settings.yaml"),
+		"product_install_full_path: \""+filepath.ToSlash(filepath.Join(game, "missing"))+"\"\r\nshould_repair: false\r\n")
+
+	ri, err := GetRiotInstallInfo(programData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ri.ClientPath != client || ri.ClientVersion != "0.1.0" {
+		t.Errorf("client %q version %q", ri.ClientPath, ri.ClientVersion)
+	}
+	if len(ri.Valorant) != 2 {
+		t.Fatalf("%d installs: %+v", len(ri.Valorant), ri.Valorant)
+	}
+
+	v := ri.Valorant[0]
+	if v.Patchline != "live" || v.Path != game || !v.Installed || !v.ShouldRepair || v.Version != "10.04.00.2647584" {
+		t.Errorf("live: %+v", v)
+	}
+	if v.FreeBytes == 0 {
+		t.Errorf("live: free space not read")
+	}
+	if v := ri.Valorant[1]; v.Patchline != "pbe" || v.Installed || v.ShouldRepair {
+		t.Errorf("pbe: %+v", v)
+	}
+}
+
+func TestGetRiotInstallInfoNothingInstalled(t *testing.T) {
+	ri, err := GetRiotInstallInfo(t.TempDir())
+	if err == nil || len(ri.Valorant) > 0 || ri.ClientPath != "" {
+		t.Fatalf("got %+v, %v; want an empty result and the missing client file", ri, err)
+	}
+}
+
+func TestParseFlatYAML(t *testing.T) {
+	got := ParseFlatYAML(strings.NewReader("# comment\r\na: \"quoted: value\"\r\nb: 'single'\r\nc: plain \r\nd:\r\n  nested: x\r\n- item\r\ne: \"\"\r\n"))
+	want := map[string]string{"a": "quoted: value", "b": "single", "c": "plain", "d": "", "e": ""}
+	if len(got) != len(want) {
+		t.Fatalf("got %q", got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
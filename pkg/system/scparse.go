@@ -0,0 +1,151 @@
+package system
+
+// Locale-independent parsing of `sc query` / `sc qc` output.
+// Labels and state words are translated on non-English Windows ("IN ESECUZIONE",
+// "WIRD AUSGEFÜHRT", ...), so we only rely on the numeric codes, the "[SC]"
+// error prefix and the fixed order of the fields.
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Service states as printed by sc (SERVICE_STATUS.dwCurrentState).
+const (
+	SCStateStopped         = 1
+	SCStateStartPending    = 2
+	SCStateStopPending     = 3
+	SCStateRunning         = 4
+	SCStateContinuePending = 5
+	SCStatePausePending    = 6
+	SCStatePaused          = 7
+)
+
+// sc error codes we care about.
+const (
+	SCErrAccessDenied     = 5
+	SCErrServiceNotExists = 1060
+)
+
+type SCQuery struct {
+	Exists    bool
+	ErrorCode int // 0 on success
+	State     int // SCState*, 0 when unknown
+	Type      int // SERVICE_TYPE bits, 0 when unknown
+}
+
+type SCConfig struct {
+	OK        bool
+	ErrorCode int
+	Type      int
+	StartType int // 0 Boot .. 4 Disabled, -1 when unknown
+}
+
+var (
+	reSCError = regexp.MustCompile(`\[SC\][^\r\n]*?\s(\d+)\s*:`)
+	reSCField = regexp.MustCompile(`^\s*([^:\s][^:]*?)\s*:\s*(.*)$`)
+	reSCCode  = regexp.MustCompile(`^([0-9a-fA-F]+)\b`)
+)
+
+// Field positions after SERVICE_NAME, identical in every UI language.
+const (
+	scIdxType  = 1
+	scIdxState = 2 // sc query: STATE
+	scIdxStart = 2 // sc qc:    START_TYPE
+)
+
+func ParseSCQuery(out string) SCQuery {
+	if code := scErrorCode(out); code != 0 {
+		return SCQuery{Exists: code != SCErrServiceNotExists, ErrorCode: code}
+	}
+
+	fields := scFields(out)
+	if len(fields) <= scIdxState {
+		return SCQuery{}
+	}
+	return SCQuery{
+		Exists: true,
+		Type:   scCode(fields[scIdxType], 16),
+		State:  scCode(fields[scIdxState], 10),
+	}
+}
+
+func ParseSCQC(out string) SCConfig {
+	if code := scErrorCode(out); code != 0 {
+		return SCConfig{ErrorCode: code, StartType: -1}
+	}
+
+	fields := scFields(out)
+	if len(fields) <= scIdxStart {
+		return SCConfig{StartType: -1}
+	}
+	start := -1
+	if m := reSCCode.FindStringSubmatch(fields[scIdxStart]); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			start = n
+		}
+	}
+	return SCConfig{
+		OK:        true,
+		Type:      scCode(fields[scIdxType], 16),
+		StartType: start,
+	}
+}
+
+// StartTypeName maps the Start value (registry or sc qc) to the names used in ServiceStatus.
+func StartTypeName(code int) string {
+	switch code {
+	case 0:
+		return "Boot"
+	case 1:
+		return "System"
+	case 2:
+		return "Automatic"
+	case 3:
+		return "Manual"
+	case 4:
+		return "Disabled"
+	default:
+		return "Unknown"
+	}
+}
+
+func scErrorCode(out string) int {
+	m := reSCError.FindStringSubmatch(out)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// scFields returns the values of the "LABEL : value" lines, in order.
+// "[SC] ... SUCCESS" headers and continuation lines are skipped.
+func scFields(out string) []string {
+	var vals []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(strings.TrimSpace(line), "[SC]") {
+			continue
+		}
+		m := reSCField.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		vals = append(vals, strings.TrimSpace(m[2]))
+	}
+	return vals
+}
+
+func scCode(v string, base int) int {
+	m := reSCCode.FindStringSubmatch(v)
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(m[1], base, 32)
+	if err != nil {
+		return 0
+	}
+	return int(n)
+}
@@ -0,0 +1,67 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var scLocales = []string{"en", "de", "fr", "ru", "zh", "ja"}
+
+func readSCFixture(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", "sc", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestParseSCQueryLocales(t *testing.T) {
+	for _, lang := range scLocales {
+		t.Run(lang, func(t *testing.T) {
+			q := ParseSCQuery(readSCFixture(t, "query_"+lang+".txt"))
+			if !q.Exists || q.ErrorCode != 0 || q.State != SCStateRunning || q.Type != 0x10 {
+				t.Errorf("query: %+v", q)
+			}
+
+			q = ParseSCQuery(readSCFixture(t, "missing_"+lang+".txt"))
+			if q.Exists || q.ErrorCode != SCErrServiceNotExists {
+				t.Errorf("missing: %+v", q)
+			}
+
+			// Access denied says nothing about existence; the caller asks the registry.
+			q = ParseSCQuery(readSCFixture(t, "denied_"+lang+".txt"))
+			if q.ErrorCode != SCErrAccessDenied || q.State != 0 {
+				t.Errorf("denied: %+v", q)
+			}
+		})
+	}
+}
+
+func TestParseSCQCLocales(t *testing.T) {
+	for _, lang := range scLocales {
+		t.Run(lang, func(t *testing.T) {
+			c := ParseSCQC(readSCFixture(t, "qc_"+lang+".txt"))
+			if !c.OK || c.Type != 0x1 || StartTypeName(c.StartType) != "System" {
+				t.Errorf("qc: %+v", c)
+			}
+
+			c = ParseSCQC(readSCFixture(t, "missing_"+lang+".txt"))
+			if c.OK || c.ErrorCode != SCErrServiceNotExists || c.StartType != -1 {
+				t.Errorf("missing: %+v", c)
+			}
+		})
+	}
+}
+
+func TestParseSCQueryStopped(t *testing.T) {
+	out := "\r\nSERVICE_NAME: vgk \r\n        TYPE               : 1  KERNEL_DRIVER  \r\n" +
+		"        STATE              : 1  STOPPED \r\n        WIN32_EXIT_CODE    : 1077  (0x435)\r\n"
+	if q := ParseSCQuery(out); !q.Exists || q.State != SCStateStopped || q.Type != 0x1 {
+		t.Fatalf("%+v", q)
+	}
+	if q := ParseSCQuery(""); q.Exists || q.State != 0 {
+		t.Fatalf("empty output: %+v", q)
+	}
+}
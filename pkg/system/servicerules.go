@@ -0,0 +1,160 @@
+package system
+
+// Expected configuration of the Vanguard services.
+// A vgk that is not System/Boot start or a Disabled vgc are classic VAN causes:
+// the rules below describe what a clean install looks like.
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ServiceRule struct {
+	Name         string
+	Type         string   // expected service type
+	AllowedStart []string // accepted start types
+	ImageName    string   // binary expected in the Vanguard install dir
+	StartHint    string   // what to tell the user when Start is wrong
+}
+
+var VanguardServiceRules = []ServiceRule{
+	{
+		Name:         "vgc",
+		Type:         "OwnProcess",
+		AllowedStart: []string{"Manual", "Automatic"},
+		ImageName:    "vgc.exe",
+		StartHint:    "the Riot Client cannot start vgc; set it to Manual (or Automatic) in services.msc",
+	},
+	{
+		Name:         "vgk",
+		Type:         "KernelDriver",
+		AllowedStart: []string{"System", "Boot"},
+		ImageName:    "vgk.sys",
+		StartHint:    "the driver is not loaded at boot, so Vanguard fails with VAN errors; reinstall Vanguard to restore Start=1",
+	},
+}
+
+// ValidateVanguardServices checks vgc/vgk against VanguardServiceRules.
+// Missing services are not reported here (see VGCExists/VGKExists).
+func ValidateVanguardServices(vi VanguardInfo) []ServiceIssue {
+	var issues []ServiceIssue
+	for _, rule := range VanguardServiceRules {
+		ss := vi.VGC
+		if rule.Name == "vgk" {
+			ss = vi.VGK
+		}
+		if !ss.Exists {
+			continue
+		}
+		issues = append(issues, ValidateService(rule, ss, vi.InstallPath)...)
+	}
+	return issues
+}
+
+// ValidateService checks ss against rule. When the service key could not be
+// read (ConfigError) only the start type, which has other sources, is checked.
+func ValidateService(rule ServiceRule, ss ServiceStatus, installPath string) []ServiceIssue {
+	var issues []ServiceIssue
+	add := func(field, got, want, explain string) {
+		issues = append(issues, ServiceIssue{Service: rule.Name, Field: field, Got: got, Want: want, Explain: explain})
+	}
+
+	if ss.Start != "" && ss.Start != "Unknown" && !containsFold(rule.AllowedStart, ss.Start) {
+		want := strings.Join(rule.AllowedStart, " or ")
+		add("Start", ss.Start, want,
+			fmt.Sprintf("%s start type is %s, expected %s: %s", rule.Name, ss.Start, want, rule.StartHint))
+	}
+
+	if ss.ConfigError != "" {
+		return issues
+	}
+
+	if ss.Type != "" && ss.Type != "Unknown" && !strings.EqualFold(ss.Type, rule.Type) {
+		add("Type", ss.Type, rule.Type,
+			fmt.Sprintf("%s is registered as %s instead of %s: the service entry is corrupted, reinstall Vanguard", rule.Name, ss.Type, rule.Type))
+	}
+
+	if ss.ImagePath == "" {
+		add("ImagePath", "", rule.ImageName,
+			fmt.Sprintf("%s has no ImagePath: Windows does not know which file to start, reinstall Vanguard", rule.Name))
+	} else {
+		img := CleanImagePath(ss.ImagePath)
+		if !strings.EqualFold(winBase(img), rule.ImageName) {
+			add("ImagePath", img, rule.ImageName,
+				fmt.Sprintf("%s points to %s instead of %s: the service was hijacked or left over from another install", rule.Name, img, rule.ImageName))
+		} else if installPath != "" && strings.Contains(img, `\`) &&
+			!strings.EqualFold(strings.TrimRight(winDir(img), `\`), strings.TrimRight(installPath, `\`)) {
+			add("ImagePath", img, installPath+`\`+rule.ImageName,
+				fmt.Sprintf("%s runs from %s, outside the Vanguard folder %s: a stale install is registered, reinstall Vanguard", rule.Name, winDir(img), installPath))
+		}
+	}
+
+	if len(ss.MissingDependencies) > 0 {
+		missing := strings.Join(ss.MissingDependencies, ", ")
+		add("Dependencies", missing, "installed",
+			fmt.Sprintf("%s depends on %s, which is not installed: the service cannot start (error 1075)", rule.Name, missing))
+	}
+
+	return issues
+}
+
+// CleanImagePath turns a service ImagePath into a plain file path:
+// strips quotes, the \??\ prefix and command line arguments.
+func CleanImagePath(ip string) string {
+	ip = strings.TrimSpace(ip)
+	if strings.HasPrefix(ip, `"`) {
+		ip = ip[1:]
+		if i := strings.Index(ip, `"`); i >= 0 {
+			ip = ip[:i]
+		}
+	} else {
+		low := strings.ToLower(ip)
+		for _, ext := range []string{".exe ", ".sys "} {
+			if i := strings.Index(low, ext); i > 0 {
+				ip = ip[:i+len(ext)-1]
+				break
+			}
+		}
+	}
+	ip = strings.TrimPrefix(ip, `\??\`)
+	return ip
+}
+
+// ServiceTypeName maps the registry Type DWORD of a service.
+func ServiceTypeName(t uint64) string {
+	switch t &^ 0x100 { // SERVICE_INTERACTIVE_PROCESS
+	case 0x1:
+		return "KernelDriver"
+	case 0x2:
+		return "FileSystemDriver"
+	case 0x10:
+		return "OwnProcess"
+	case 0x20:
+		return "ShareProcess"
+	default:
+		return "Unknown"
+	}
+}
+
+func winBase(p string) string {
+	if i := strings.LastIndexAny(p, `\/`); i >= 0 {
+		return p[i+1:]
+	}
+	return p
+}
+
+func winDir(p string) string {
+	if i := strings.LastIndexAny(p, `\/`); i >= 0 {
+		return p[:i]
+	}
+	return ""
+}
+
+func containsFold(list []string, v string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, v) {
+			return true
+		}
+	}
+	return false
+}
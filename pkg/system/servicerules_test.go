@@ -0,0 +1,90 @@
+package system
+
+import "testing"
+
+func TestValidateService(t *testing.T) {
+	rule := VanguardServiceRules[1] // vgk
+	good := ServiceStatus{Exists: true, Start: "System", Type: "KernelDriver",
+		ImagePath: `\??\C:\Program Files\Riot Vanguard\vgk.sys`}
+	if issues := ValidateService(rule, good, `C:\Program Files\Riot Vanguard`); len(issues) != 0 {
+		t.Fatalf("clean vgk reported: %+v", issues)
+	}
+
+	noImage := good
+	noImage.ImagePath = ""
+	if issues := ValidateService(rule, noImage, ""); len(issues) != 1 || issues[0].Field != "ImagePath" {
+		t.Fatalf("missing ImagePath: %+v", issues)
+	}
+
+	// An unreadable key leaves ImagePath empty: that is not a broken install.
+	unreadable := ServiceStatus{Exists: true, Start: "System", Type: "Unknown", ConfigError: "Access is denied."}
+	if issues := ValidateService(rule, unreadable, ""); len(issues) != 0 {
+		t.Fatalf("unreadable key reported: %+v", issues)
+	}
+	unreadable.Start = "Disabled"
+	if issues := ValidateService(rule, unreadable, ""); len(issues) != 1 || issues[0].Field != "Start" {
+		t.Fatalf("start type not checked: %+v", issues)
+	}
+}
+
+func TestCleanImagePath(t *testing.T) {
+	for in, want := range map[string]string{
+		`"C:\Program Files\Riot Vanguard\vgc.exe"`:        `C:\Program Files\Riot Vanguard\vgc.exe`,
+		`\??\C:\Program Files\Riot Vanguard\vgk.sys`:      `C:\Program Files\Riot Vanguard\vgk.sys`,
+		`C:\Program Files\Riot Vanguard\vgc.exe -service`: `C:\Program Files\Riot Vanguard\vgc.exe`,
+		`"C:\Program Files\Riot Vanguard\vgc.exe" -k svc`: `C:\Program Files\Riot Vanguard\vgc.exe`,
+		`  System32\drivers\vgk.sys `:                     `System32\drivers\vgk.sys`,
+	} {
+		if got := CleanImagePath(in); got != want {
+			t.Errorf("CleanImagePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValidateServiceVGC(t *testing.T) {
+	const install = `C:\Program Files\Riot Vanguard`
+	rule := VanguardServiceRules[0] // vgc
+	good := ServiceStatus{Exists: true, Start: "Manual", Type: "OwnProcess",
+		ImagePath: `"C:\Program Files\Riot Vanguard\vgc.exe" -service`}
+
+	for _, tc := range []struct {
+		name  string
+		edit  func(ss *ServiceStatus)
+		field string // "" = no issue
+	}{
+		{"clean, quoted path with arguments", func(ss *ServiceStatus) {}, ""},
+		{"automatic start", func(ss *ServiceStatus) { ss.Start = "Automatic" }, ""},
+		{"disabled", func(ss *ServiceStatus) { ss.Start = "Disabled" }, "Start"},
+		{"kernel driver type", func(ss *ServiceStatus) { ss.Type = "KernelDriver" }, "Type"},
+		{"other binary", func(ss *ServiceStatus) { ss.ImagePath = `C:\Users\Public\svchost.exe` }, "ImagePath"},
+		{"vgc.exe outside the install dir", func(ss *ServiceStatus) { ss.ImagePath = `"C:\Temp\Riot Vanguard\vgc.exe"` }, "ImagePath"},
+		{"missing dependency", func(ss *ServiceStatus) { ss.MissingDependencies = []string{"vgk"} }, "Dependencies"},
+	} {
+		ss := good
+		tc.edit(&ss)
+		issues := ValidateService(rule, ss, install)
+		switch {
+		case tc.field == "" && len(issues) != 0:
+			t.Errorf("%s: unexpected issues %+v", tc.name, issues)
+		case tc.field != "" && (len(issues) != 1 || issues[0].Field != tc.field || issues[0].Service != "vgc"):
+			t.Errorf("%s: issues %+v, want one %s issue", tc.name, issues, tc.field)
+		}
+	}
+}
+
+func TestServiceTypeName(t *testing.T) {
+	for in, want := range map[uint64]string{
+		0x1:   "KernelDriver",
+		0x2:   "FileSystemDriver",
+		0x10:  "OwnProcess",
+		0x20:  "ShareProcess",
+		0x110: "OwnProcess", // interactive
+		0x4:   "Unknown",    // adapter
+		0x50:  "Unknown",    // user service
+		0:     "Unknown",
+	} {
+		if got := ServiceTypeName(in); got != want {
+			t.Errorf("ServiceTypeName(0x%x) = %q, want %q", in, got, want)
+		}
+	}
+}
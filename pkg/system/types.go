@@ -1,5 +1,10 @@
 package system
 
+import (
+	"crypto/x509"
+	"time"
+)
+
 type TPMInfo struct {
 	Present bool   `json:"present"`
 	Ready   bool   `json:"ready"`
@@ -25,6 +30,113 @@ type SecureBootKeys struct {
 
 type BootInfo struct {
 	BIOSMode string `json:"biosMode"` // "UEFI" / "Legacy" / "Unknown"
+
+	BootedViaCSM     bool `json:"bootedViaCsm"`     // Windows started through the legacy BIOS / CSM path; the only CSM signal we have
+	FirmwareVarsRead bool `json:"firmwareVarsRead"` // UEFI variables were readable (needs admin)
+	SecureBootVar    bool `json:"secureBootVar"`    // EFI "SecureBoot" variable
+	SetupMode        bool `json:"setupMode"`        // EFI "SetupMode": no Platform Key enrolled
+	FastStartup      bool `json:"fastStartup"`      // Windows Fast Startup (hiberboot)
+}
+
+// UEFIBootEntry is one Boot#### load option.
+type UEFIBootEntry struct {
+	Number             uint16 `json:"number"`
+	Name               string `json:"name"` // "Boot0001"
+	Description        string `json:"description"`
+	DevicePath         string `json:"devicePath"`
+	FilePath           string `json:"filePath,omitempty"` // loader on the ESP, e.g. \EFI\Microsoft\Boot\bootmgfw.efi
+	Active             bool   `json:"active"`
+	Hidden             bool   `json:"hidden"`
+	Current            bool   `json:"current"` // BootCurrent: the entry this boot used
+	WindowsBootManager bool   `json:"windowsBootManager"`
+}
+
+type UEFIBootOrder struct {
+	BootOrder    []uint16        `json:"bootOrder"`
+	BootCurrent  int             `json:"bootCurrent"` // -1 when unknown
+	Entries      []UEFIBootEntry `json:"entries"`     // in BootOrder order
+	First        string          `json:"first"`       // description of the first active entry
+	WindowsFirst bool            `json:"windowsFirst"`
+}
+
+// PESignature is the Authenticode signature of a PE file (.exe, .sys, .efi).
+type PESignature struct {
+	Arch        string `json:"arch"`   // x64 / ia32 / aa64 / arm
+	Signed      bool   `json:"signed"` // has a certificate table
+	Valid       bool   `json:"valid"`  // image hash and signer signature match (trust not evaluated)
+	Error       string `json:"error,omitempty"`
+	DigestAlg   string `json:"digestAlg,omitempty"`
+	Signer      string `json:"signer,omitempty"` // subject CN of the signing certificate
+	SignerOrg   string `json:"signerOrg,omitempty"`
+	Issuer      string `json:"issuer,omitempty"`
+	ImageSHA256 string `json:"imageSha256"` // Authenticode hash, as listed in db/dbx
+
+	SigningTime time.Time     `json:"signingTime"` // from the timestamp, zero when not timestamped
+	Nested      []PESignature `json:"nested,omitempty"`
+
+	Certs []*x509.Certificate `json:"-"` // signer first, then the other embedded certificates
+}
+
+type ValorantInstall struct {
+	Patchline    string `json:"patchline"` // live / pbe
+	Path         string `json:"path"`
+	Installed    bool   `json:"installed"` // the folder exists
+	Version      string `json:"version"`   // VALORANT-Win64-Shipping.exe FileVersion
+	ShouldRepair bool   `json:"shouldRepair"`
+	FreeBytes    uint64 `json:"freeBytes"` // free space on the install volume
+}
+
+type RiotInstallInfo struct {
+	ProgramData   string            `json:"programData"`
+	ClientPath    string            `json:"clientPath"` // RiotClientServices.exe
+	ClientVersion string            `json:"clientVersion"`
+	Valorant      []ValorantInstall `json:"valorant"` // live first
+}
+
+// IntegrityReport compares the Vanguard install folder with a known-good manifest.
+type IntegrityReport struct {
+	Checked        bool     `json:"checked"` // a manifest for this version was found
+	Version        string   `json:"version"`
+	ManifestSource string   `json:"manifestSource"` // "bundled" or the file given with -vanguard-manifest
+	Files          int      `json:"files"`
+	Missing        []string `json:"missing"`
+	Extra          []string `json:"extra"`
+	Modified       []string `json:"modified"`
+	Unreadable     []string `json:"unreadable"` // could not be hashed (locked, access denied)
+}
+
+// PEVersionInfo is the VS_VERSIONINFO resource of a PE file.
+type PEVersionInfo struct {
+	FileVersion      string `json:"fileVersion"` // string table, falls back to the fixed version
+	ProductVersion   string `json:"productVersion"`
+	CompanyName      string `json:"companyName"`
+	FileDescription  string `json:"fileDescription"`
+	ProductName      string `json:"productName"`
+	OriginalFilename string `json:"originalFilename"`
+
+	FixedFileVersion    string `json:"fixedFileVersion"` // VS_FIXEDFILEINFO, "a.b.c.d"
+	FixedProductVersion string `json:"fixedProductVersion"`
+}
+
+// BinarySignature is the verdict on a Vanguard binary's signature.
+type BinarySignature struct {
+	File   string `json:"file"`
+	Status string `json:"status"` // see SigStatus*
+	Signer string `json:"signer"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type ESPBinary struct {
+	Path       string      `json:"path"` // relative to the ESP root
+	Signature  PESignature `json:"signature"`
+	SecureBoot string      `json:"secureBoot"` // Allowed / Revoked / NotTrusted / Unsigned / Invalid / Unknown
+}
+
+// ESPInventory lists the EFI binaries on the EFI System Partition.
+type ESPInventory struct {
+	Root     string      `json:"root"`
+	DBRead   bool        `json:"dbRead"` // db/dbx could be read from the firmware
+	Binaries []ESPBinary `json:"binaries"`
 }
 
 type DiskInfo struct {
@@ -35,22 +147,90 @@ type VirtualizationInfo struct {
 	HypervisorPresent bool `json:"hypervisorPresent"`
 	HyperVEnabled     bool `json:"hyperVEnabled"`
 	VBS_Enabled       bool `json:"vbsEnabled"`
+
+	VBSStatus                   string   `json:"vbsStatus"` // Off / Configured / Running
+	SecurityServicesConfigured  []string `json:"securityServicesConfigured"`
+	SecurityServicesRunning     []string `json:"securityServicesRunning"`
+	RequiredSecurityProperties  []string `json:"requiredSecurityProperties"`
+	AvailableSecurityProperties []string `json:"availableSecurityProperties"`
+	CodeIntegrityPolicy         string   `json:"codeIntegrityPolicy"`         // Off / Audit / Enforced
+	UserModeCodeIntegrityPolicy string   `json:"userModeCodeIntegrityPolicy"` // Off / Audit / Enforced
+
+	Hypervisors HypervisorInventory `json:"hypervisors"`
 }
 
-type ServiceStatus struct {
-	Exists  bool   `json:"exists"`
+// BitLockerInfo is the BitLocker state of the OS volume.
+type BitLockerInfo struct {
+	Read                 bool     `json:"read"`
+	MountPoint           string   `json:"mountPoint"`
+	VolumeStatus         string   `json:"volumeStatus"` // FullyEncrypted / FullyDecrypted / EncryptionInProgress ...
+	ProtectionOn         bool     `json:"protectionOn"` // false when off or suspended
+	EncryptionPercentage int      `json:"encryptionPercentage"`
+	Protectors           []string `json:"protectors"` // Tpm, TpmPin, RecoveryPassword, ...
+	HasRecoveryPassword  bool     `json:"hasRecoveryPassword"`
+	PCRs                 []int    `json:"pcrs"`      // TPM validation profile
+	PCR7Bound            bool     `json:"pcr7Bound"` // sealed to the Secure Boot state
+}
+
+// HypervisorFeature is a Windows optional feature tied to the hypervisor.
+type HypervisorFeature struct {
+	Name       string `json:"name"`
+	Title      string `json:"title"`
+	State      string `json:"state"` // Enabled / Disabled / Absent, "" when unknown
+	Enabled    bool   `json:"enabled"`
+	Hypervisor bool   `json:"hypervisor"` // enabling it alone starts the hypervisor
+	Provides   string `json:"provides"`   // what turning it off takes away
+}
+
+// HypervisorProduct is an installed third-party hypervisor.
+type HypervisorProduct struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"` // service/driver name
 	Running bool   `json:"running"`
-	Start   string `json:"start"` // Automatic/Manual/Disabled/Unknown
-	Raw     string `json:"raw"`
+	Note    string `json:"note"`
+}
+
+type HypervisorInventory struct {
+	Features []HypervisorFeature `json:"features"`
+	Products []HypervisorProduct `json:"products"`
+}
+
+type ServiceStatus struct {
+	Exists              bool     `json:"exists"`
+	Running             bool     `json:"running"`
+	Start               string   `json:"start"` // Automatic/Manual/Disabled/Unknown
+	Type                string   `json:"type"`  // KernelDriver/FileSystemDriver/OwnProcess/ShareProcess/Unknown
+	ImagePath           string   `json:"imagePath"`
+	Dependencies        []string `json:"dependencies"`
+	MissingDependencies []string `json:"missingDependencies"`
+	ConfigError         string   `json:"configError,omitempty"` // service key could not be read
+	Raw                 string   `json:"raw"`
+}
+
+type ServiceIssue struct {
+	Service string `json:"service"`
+	Field   string `json:"field"` // Start/Type/ImagePath/Dependencies
+	Got     string `json:"got"`
+	Want    string `json:"want"`
+	Explain string `json:"explain"`
 }
 
 type VanguardInfo struct {
-	Installed     bool          `json:"installed"`
-	InstallPath   string        `json:"installPath"`
-	Version       string        `json:"version"`
-	VGC           ServiceStatus `json:"vgc"`
-	VGK           ServiceStatus `json:"vgk"`
-	DriverPresent bool          `json:"driverPresent"`
+	Installed     bool           `json:"installed"`
+	InstallPath   string         `json:"installPath"`
+	Version       string         `json:"version"`
+	VGC           ServiceStatus  `json:"vgc"`
+	VGK           ServiceStatus  `json:"vgk"`
+	DriverPresent bool           `json:"driverPresent"`
+	ServiceIssues []ServiceIssue `json:"serviceIssues"`
+	DriverTime    time.Time      `json:"driverTime"` // vgk.sys last write (install/update)
+	LastBoot      time.Time      `json:"lastBoot"`
+	State         string         `json:"state"` // see VanguardState*
+	StateHint     string         `json:"stateHint"`
+
+	DriverVersion string          `json:"driverVersion"` // vgk.sys FileVersion
+	VGCSignature  BinarySignature `json:"vgcSignature"`
+	VGKSignature  BinarySignature `json:"vgkSignature"`
 }
 
 type SystemInfo struct {
@@ -59,4 +239,193 @@ type SystemInfo struct {
 	RAMGiB      int    `json:"ramGiB"`
 	Motherboard string `json:"motherboard"`
 	OS          string `json:"os"`
+
+	OSBuild        int       `json:"osBuild"` // 19045, 22631, 26100...
+	OSUBR          int       `json:"osUbr"`
+	DisplayVersion string    `json:"displayVersion"` // 22H2 / 23H2 / 24H2
+	Edition        string    `json:"edition"`        // Professional / Core / Enterprise...
+	Architecture   string    `json:"architecture"`   // 64-bit / ARM 64-bit
+	InstallDate    time.Time `json:"installDate"`
+}
+
+type VanguardLogSummary struct {
+	Found              bool      `json:"found"`
+	File               string    `json:"file"`
+	SessionStart       time.Time `json:"sessionStart"`
+	SessionEnd         time.Time `json:"sessionEnd"`
+	Lines              int       `json:"lines"`
+	ErrorCodes         []string  `json:"errorCodes"`     // "VAN 68", "0xC0000428", ...
+	DriverFailures     []string  `json:"driverFailures"` // raw lines about vgk/driver load failures (last 10)
+	DriverFailureCount int       `json:"driverFailureCount"`
+	Errors             []string  `json:"errors"` // other error lines (capped)
+}
+
+type GameCrash struct {
+	Folder  string    `json:"folder"`
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"` // Crash / Assert / Ensure / GPUCrash
+	Error   string    `json:"error"`
+	HasDump bool      `json:"hasDump"`
+}
+
+// GameLogSummary is the diagnosis of the last Valorant session (ShooterGame.log).
+type GameLogSummary struct {
+	Found         bool        `json:"found"`
+	File          string      `json:"file"`
+	SessionStart  time.Time   `json:"sessionStart"`
+	SessionEnd    time.Time   `json:"sessionEnd"`
+	Lines         int         `json:"lines"`
+	ErrorCodes    []string    `json:"errorCodes"`    // "VAN 68", "VAL 43", ...
+	Fatal         []string    `json:"fatal"`         // fatal / critical error lines (capped)
+	GPUDeviceLost []string    `json:"gpuDeviceLost"` // DXGI device removed / hung lines
+	AntiCheat     []string    `json:"antiCheat"`     // Vanguard disconnects and errors
+	Crashes       []GameCrash `json:"crashes"`       // newest crash reports
+}
+
+// EventRecord is one decoded Windows event log (.evtx) record.
+type EventRecord struct {
+	RecordID uint64      `json:"recordId"`
+	Time     time.Time   `json:"time"`
+	Provider string      `json:"provider"`
+	EventID  int         `json:"eventId"`
+	Level    int         `json:"level"` // 1 critical, 2 error, 3 warning, 4 information
+	Channel  string      `json:"channel"`
+	Computer string      `json:"computer"`
+	Data     []EventData `json:"data"` // EventData / UserData fields, in order
+}
+
+type EventData struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+// EventLogEvent is an event log record relevant to Vanguard or the boot chain.
+type EventLogEvent struct {
+	EventRecord
+	Category string `json:"category"` // see EventCategory*
+	Summary  string `json:"summary"`
+}
+
+// EventLogReport lists the relevant events of one log, newest first.
+type EventLogReport struct {
+	Source  string          `json:"source"`
+	Records int             `json:"records"`
+	Events  []EventLogEvent `json:"events"`
+}
+
+// DumpModule is a driver loaded at crash time.
+type DumpModule struct {
+	Name      string    `json:"name"`
+	Base      uint64    `json:"base"`
+	Size      uint32    `json:"size"`
+	TimeStamp time.Time `json:"timeStamp"` // link time of the image
+}
+
+// CrashDump is the analysis of one kernel crash dump (blue screen).
+type CrashDump struct {
+	File             string       `json:"file"`
+	Time             time.Time    `json:"time"`
+	DumpType         string       `json:"dumpType"` // minidump / kernel / complete
+	BuildNumber      uint32       `json:"buildNumber"`
+	BugCheckCode     uint32       `json:"bugCheckCode"`
+	BugCheckName     string       `json:"bugCheckName"`
+	BugCheckParams   [4]uint64    `json:"bugCheckParams"`
+	FaultAddress     uint64       `json:"faultAddress"`
+	FaultModule      string       `json:"faultModule"`
+	StackModules     []string     `json:"stackModules"`
+	Culprit          string       `json:"culprit"` // likely driver, "" when unknown
+	VanguardInvolved bool         `json:"vanguardInvolved"`
+	Note             string       `json:"note,omitempty"`
+	Modules          []DumpModule `json:"modules,omitempty"`
+}
+
+// MinidumpScan lists the newest small memory dumps, newest first.
+type MinidumpScan struct {
+	Dir   string      `json:"dir"`
+	Dumps []CrashDump `json:"dumps"`
+}
+
+// Mitigation is one element of an Exploit Protection policy (DEP, ASLR,
+// ControlFlowGuard, ...) with its attributes as exported.
+type Mitigation struct {
+	Name     string            `json:"name"`
+	Settings map[string]string `json:"settings"`
+}
+
+type ProcessMitigations struct {
+	Executable  string       `json:"executable"`
+	Mitigations []Mitigation `json:"mitigations"`
+}
+
+// ExploitProtection is the process mitigation policy: system-wide settings and
+// the overrides of vgc.exe and the game.
+type ExploitProtection struct {
+	Source    string               `json:"source"`
+	Read      bool                 `json:"read"`
+	System    []Mitigation         `json:"system"`
+	Processes []ProcessMitigations `json:"processes"`
+	Problems  []string             `json:"problems"`            // known-bad settings
+	DEPPolicy string               `json:"depPolicy,omitempty"` // boot-time policy, see DEP*
+}
+
+type DriverEntry struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Type        string `json:"type"`
+	StartMode   string `json:"startMode"`
+	State       string `json:"state"` // localized, as printed by driverquery
+	Path        string `json:"path"`
+	OrigName    string `json:"origName"` // OriginalFilename of the version resource
+}
+
+type VulnerableDriver struct {
+	Name      string `json:"name"`
+	File      string `json:"file"`
+	Path      string `json:"path"`
+	Vendor    string `json:"vendor"`
+	Reason    string `json:"reason"`
+	State     string `json:"state"`
+	Loaded    bool   `json:"loaded"`    // running according to the service control manager
+	MatchedBy string `json:"matchedBy"` // "name" / "original name"
+}
+
+type DriverScan struct {
+	Scanned          int                `json:"scanned"`
+	BlocklistVersion string             `json:"blocklistVersion"`
+	Vulnerable       []VulnerableDriver `json:"vulnerable"`
+}
+
+type AntivirusProduct struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+type ConflictProduct struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`   // "anticheat" / "antivirus"
+	Source  string `json:"source"` // service name or antivirus display name
+	Running bool   `json:"running"`
+	Note    string `json:"note"`
+}
+
+type ConflictInfo struct {
+	Antivirus []AntivirusProduct `json:"antivirus"`
+	Conflicts []ConflictProduct  `json:"conflicts"`
+}
+
+type DMAInfo struct {
+	KernelDMAProtection bool     `json:"kernelDmaProtection"`
+	IOMMUAvailable      bool     `json:"iommuAvailable"`
+	IOMMUs              []string `json:"iommus"` // Linux: /sys/class/iommu entries
+	Source              string   `json:"source"`
+}
+
+type BCDInfo struct {
+	TestSigning          bool   `json:"testSigning"`
+	Debug                bool   `json:"debug"`
+	NoIntegrityChecks    bool   `json:"noIntegrityChecks"`
+	HypervisorLaunchType string `json:"hypervisorLaunchType"` // Off / Auto / "" when not set
+	BootMenuPolicy       string `json:"bootMenuPolicy"`       // Standard / Legacy / "" when not set
+	StartOptions         string `json:"startOptions"`         // SystemStartOptions of the running kernel
+	Raw                  string `json:"raw"`
 }
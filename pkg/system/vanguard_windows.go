@@ -3,13 +3,14 @@
 package system
 
 import (
-	"bytes"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"time"
 
+	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
 )
 
@@ -31,11 +32,22 @@ func GetVanguardInfo() (VanguardInfo, error) {
 
 	if vi.InstallPath != "" {
 		vgk := filepath.Join(vi.InstallPath, "vgk.sys")
-		if _, err := os.Stat(vgk); err == nil {
+		if st, err := os.Stat(vgk); err == nil {
 			vi.DriverPresent = true
+			vi.DriverTime = st.ModTime()
+			if dv, err := ReadVersionInfo(vgk); err == nil {
+				vi.DriverVersion = dv.FileVersion
+			}
 		}
+		vi.VGCSignature = CheckBinarySignature(filepath.Join(vi.InstallPath, "vgc.exe"), nil)
+		vi.VGKSignature = CheckBinarySignature(filepath.Join(vi.InstallPath, "vgk.sys"), nil)
 	}
 
+	vi.ServiceIssues = ValidateVanguardServices(vi)
+
+	vi.LastBoot = time.Now().Add(-windows.DurationSinceBoot()).Truncate(time.Second)
+	vi.State, vi.StateHint = VanguardRuntimeState(vi)
+
 	return vi, nil
 }
 
@@ -60,19 +72,17 @@ func serviceImageDir(service string) string {
 	}
 	defer k.Close()
 
-	ip, _, err := k.GetStringValue("ImagePath")
+	ip, typ, err := k.GetStringValue("ImagePath")
 	if err != nil {
 		return ""
 	}
-
-	ip = strings.TrimSpace(ip)
-	ip = strings.Trim(ip, "\"")
-	ip = strings.TrimPrefix(ip, "\\??\\")
-	if i := strings.Index(ip, " "); i > 0 {
-		ip = ip[:i]
+	if typ == registry.EXPAND_SZ {
+		if x, err := registry.ExpandString(ip); err == nil {
+			ip = x
+		}
 	}
 
-	dir := filepath.Dir(ip)
+	dir := filepath.Dir(CleanImagePath(ip))
 	if _, err := os.Stat(dir); err == nil {
 		return dir
 	}
@@ -83,37 +93,32 @@ func detectVanguardVersion(installPath string) string {
 	if installPath == "" {
 		return ""
 	}
-	vgc := filepath.Join(installPath, "vgc.exe")
-	if _, err := os.Stat(vgc); err != nil {
+	vi, err := ReadVersionInfo(filepath.Join(installPath, "vgc.exe"))
+	if err != nil {
 		return ""
 	}
-
-	cmd := exec.Command(
-		"powershell.exe", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass",
-		"-Command", "(Get-Item '"+escapePSPath(vgc)+"').VersionInfo.FileVersion",
-	)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	_ = cmd.Run()
-	return strings.TrimSpace(out.String())
+	return vi.FileVersion
 }
 
-func escapePSPath(p string) string { return strings.ReplaceAll(p, "'", "''") }
-
 func getServiceStatus(name string) ServiceStatus {
 	var ss ServiceStatus
 
-	// exists/running via sc query
+	// exists/running via sc query (numeric codes only, any UI language)
 	q := run("sc", "query", name)
 	ss.Raw = q
-	low := strings.ToLower(q)
-	if strings.Contains(low, "does not exist") || strings.Contains(low, "non esiste") || strings.Contains(low, "failed") {
+	sq := ParseSCQuery(q)
+	if sq.ErrorCode != 0 && sq.ErrorCode != SCErrServiceNotExists {
+		// e.g. access denied: trust the registry for existence
+		sq.Exists = serviceKeyExists(name)
+	}
+	if !sq.Exists {
 		ss.Exists = false
 		ss.Start = "Unknown"
 		return ss
 	}
 	ss.Exists = true
-	ss.Running = strings.Contains(strings.ToUpper(q), "RUNNING") || strings.Contains(strings.ToUpper(q), "IN ESECUZIONE")
+	ss.Running = sq.State == SCStateRunning
+	readServiceConfig(name, &ss)
 
 	if start, ok := getStartTypeFromPowerShell(name); ok && start != "Unknown" {
 		ss.Start = start
@@ -134,6 +139,57 @@ func getServiceStatus(name string) ServiceStatus {
 	return ss
 }
 
+// readServiceConfig fills Type, ImagePath and dependencies from the service registry key.
+// ConfigError is set when the key cannot be read, so that an empty ImagePath is
+// not mistaken for a broken install.
+func readServiceConfig(service string, ss *ServiceStatus) {
+	ss.Type = "Unknown"
+
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+service, registry.QUERY_VALUE)
+	if err != nil {
+		ss.ConfigError = err.Error()
+		return
+	}
+	defer k.Close()
+
+	if t, _, err := k.GetIntegerValue("Type"); err == nil {
+		ss.Type = ServiceTypeName(t)
+	}
+	ip, typ, err := k.GetStringValue("ImagePath")
+	if err == nil && typ == registry.EXPAND_SZ {
+		// Usually "%SystemRoot%\..." or "%ProgramFiles%\..."
+		if x, xerr := registry.ExpandString(ip); xerr == nil {
+			ip = x
+		}
+	}
+	switch {
+	case err == nil:
+		ss.ImagePath = ip
+	case !errors.Is(err, registry.ErrNotExist):
+		ss.ConfigError = "ImagePath: " + err.Error()
+	}
+	if deps, _, err := k.GetStringsValue("DependOnService"); err == nil {
+		for _, d := range deps {
+			if d = strings.TrimSpace(d); d == "" {
+				continue
+			}
+			ss.Dependencies = append(ss.Dependencies, d)
+			if !serviceKeyExists(d) {
+				ss.MissingDependencies = append(ss.MissingDependencies, d)
+			}
+		}
+	}
+}
+
+func serviceKeyExists(service string) bool {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+service, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	_ = k.Close()
+	return true
+}
+
 func getStartTypeFromPowerShell(name string) (string, bool) {
 	cmd := exec.Command(
 		"powershell.exe", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass",
@@ -161,57 +217,15 @@ func getStartFromRegistry(service string) string {
 
 	// For drivers/services:
 	// 0 Boot, 1 System, 2 Automatic, 3 Manual, 4 Disabled
-	switch v {
-	case 0:
-		return "Boot"
-	case 1:
-		return "System"
-	case 2:
-		return "Automatic"
-	case 3:
-		return "Manual"
-	case 4:
-		return "Disabled"
-	default:
-		return "Unknown"
-	}
+	return StartTypeName(int(v))
 }
 
 func getStartFromSCQC(service string) string {
-	qc := run("sc", "qc", service)
-
-	re := regexp.MustCompile(`(?i)START_TYPE\s*:\s*(\d)`)
-	m := re.FindStringSubmatch(qc)
-	if len(m) == 2 {
-		switch m[1] {
-		case "0":
-			return "Boot"
-		case "1":
-			return "System"
-		case "2":
-			return "Automatic"
-		case "3":
-			return "Manual"
-		case "4":
-			return "Disabled"
-		}
-	}
-
-	up := strings.ToUpper(qc)
-	switch {
-	case strings.Contains(up, "BOOT_START"):
-		return "Boot"
-	case strings.Contains(up, "SYSTEM_START"):
-		return "System"
-	case strings.Contains(up, "AUTO_START") || strings.Contains(up, "AUTOMATIC"):
-		return "Automatic"
-	case strings.Contains(up, "DEMAND_START") || strings.Contains(up, "MANUAL"):
-		return "Manual"
-	case strings.Contains(up, "DISABLED"):
-		return "Disabled"
-	default:
+	qc := ParseSCQC(run("sc", "qc", service))
+	if !qc.OK {
 		return "Unknown"
 	}
+	return StartTypeName(qc.StartType)
 }
 
 func normalizeStartType(s string) string {
@@ -0,0 +1,191 @@
+package system
+
+// Riot Vanguard log analysis.
+// Vanguard keeps plain text logs under <InstallPath>\Logs. We only look at the
+// most recent file and, inside it, at the last session (everything after the
+// last line whose message is a "service start" style marker).
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxLogErrors         = 10
+	maxLogDriverFailures = 10
+)
+
+var (
+	reLogTimestamp = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})[ T](\d{2}:\d{2}:\d{2})`)
+	reLogTimeSlash = regexp.MustCompile(`(\d{2}/\d{2}/\d{4}) (\d{2}:\d{2}:\d{2})`)
+	reVANCode      = regexp.MustCompile(`(?i)\bVAN\s*[:#]?\s*(-?\d{1,5})\b`)
+	reNTStatus     = regexp.MustCompile(`\b0[xX][cC]0[0-9a-fA-F]{6}\b`)
+	reLogPrefix    = regexp.MustCompile(`^(?:[\[(]?\d{2,4}[-/]\d{2}[-/]\d{2,4}[ T]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?[\])]?\s*)?(?:\[[^\]]*\]\s*|(?:info|warn(?:ing)?|error|debug|trace)\s*[:|-]?\s*)*`)
+)
+
+// Messages that open a new vgc/vgk session. Matched lower-case against the
+// start of the message, after the timestamp and [level]/[component] tags.
+var logSessionMarkers = []string{
+	"session start",
+	"service start",
+	"starting vgc",
+	"vgc start",
+	"vanguard start",
+}
+
+// AnalyzeVanguardLogs summarizes the last session of the newest log file found
+// under installPath\Logs (or installPath itself).
+func AnalyzeVanguardLogs(installPath string) (VanguardLogSummary, error) {
+	if installPath == "" {
+		// Not installed: nothing to analyze, VGCExists/VGKExists report it.
+		return VanguardLogSummary{}, nil
+	}
+
+	file := newestLogFile(filepath.Join(installPath, "Logs"))
+	if file == "" {
+		file = newestLogFile(installPath)
+	}
+	if file == "" {
+		return VanguardLogSummary{}, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return VanguardLogSummary{File: file}, err
+	}
+	defer f.Close()
+
+	sum, err := ParseVanguardLog(f)
+	sum.File = file
+	return sum, err
+}
+
+// ParseVanguardLog reads a Vanguard log and returns the summary of its last session.
+func ParseVanguardLog(r io.Reader) (VanguardLogSummary, error) {
+	var sum VanguardLogSummary
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(sc.Text(), "\ufeff"))
+		if line == "" {
+			continue
+		}
+		low := strings.ToLower(line)
+		ts := parseLogTime(line)
+
+		if isSessionStart(low) {
+			sum = VanguardLogSummary{SessionStart: ts}
+		}
+		sum.Found = true
+		sum.Lines++
+		if !ts.IsZero() {
+			if sum.SessionStart.IsZero() {
+				sum.SessionStart = ts
+			}
+			sum.SessionEnd = ts
+		}
+
+		for _, m := range reVANCode.FindAllStringSubmatch(line, -1) {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				sum.ErrorCodes = appendUnique(sum.ErrorCodes, "VAN "+strconv.Itoa(n))
+			}
+		}
+		for _, m := range reNTStatus.FindAllString(line, -1) {
+			sum.ErrorCodes = appendUnique(sum.ErrorCodes, "0x"+strings.ToUpper(m[2:]))
+		}
+
+		switch {
+		case isDriverLoadFailure(low):
+			// Keep the most recent lines; a looping retry can log thousands.
+			sum.DriverFailureCount++
+			if len(sum.DriverFailures) == maxLogDriverFailures {
+				sum.DriverFailures = append(sum.DriverFailures[:0], sum.DriverFailures[1:]...)
+			}
+			sum.DriverFailures = append(sum.DriverFailures, line)
+		case strings.Contains(low, "error") || strings.Contains(low, "fail"):
+			if len(sum.Errors) < maxLogErrors {
+				sum.Errors = append(sum.Errors, line)
+			}
+		}
+	}
+	return sum, sc.Err()
+}
+
+func newestLogFile(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var best string
+	var bestMod time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".log") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if best == "" || info.ModTime().After(bestMod) {
+			best = filepath.Join(dir, e.Name())
+			bestMod = info.ModTime()
+		}
+	}
+	return best
+}
+
+func parseLogTime(line string) time.Time {
+	if m := reLogTimestamp.FindStringSubmatch(line); len(m) == 3 {
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", m[1]+" "+m[2], time.Local); err == nil {
+			return t
+		}
+	}
+	if m := reLogTimeSlash.FindStringSubmatch(line); len(m) == 3 {
+		if t, err := time.ParseInLocation("01/02/2006 15:04:05", m[1]+" "+m[2], time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// isSessionStart reports whether the message of the line is a session marker.
+// "vgk service start failed" or "[error] service start failed" are not.
+func isSessionStart(low string) bool {
+	msg := low[len(reLogPrefix.FindString(low)):]
+	if strings.Contains(msg, "fail") || strings.Contains(msg, "error") {
+		return false
+	}
+	for _, m := range logSessionMarkers {
+		if strings.HasPrefix(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDriverLoadFailure(low string) bool {
+	if !strings.Contains(low, "vgk") && !strings.Contains(low, "driver") && !strings.Contains(low, ".sys") {
+		return false
+	}
+	if !strings.Contains(low, "load") && !strings.Contains(low, "start") {
+		return false
+	}
+	return strings.Contains(low, "fail") || strings.Contains(low, "error") ||
+		strings.Contains(low, "unable") || strings.Contains(low, "could not")
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, x := range list {
+		if x == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
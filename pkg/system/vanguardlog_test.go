@@ -0,0 +1,77 @@
+package system
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeVanguardLogs(t *testing.T) {
+	sum, err := AnalyzeVanguardLogs(filepath.Join("testdata", "vanguard"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sum.Found || filepath.Base(sum.File) != "vgc.log" {
+		t.Fatalf("log not found: %+v", sum)
+	}
+
+	// The last session opens at the second "Service start"; the failed
+	// start lines must not reset it.
+	if got := sum.SessionStart.Format("2006-01-02 15:04:05"); got != "2026-03-02 21:00:00" {
+		t.Errorf("SessionStart = %s", got)
+	}
+	if got := sum.SessionEnd.Format("2006-01-02 15:04:05"); got != "2026-03-03 00:15:30" {
+		t.Errorf("SessionEnd = %s", got)
+	}
+	if sum.Lines != 16 {
+		t.Errorf("Lines = %d, want 16", sum.Lines)
+	}
+	if got := strings.Join(sum.ErrorCodes, ","); got != "0xC0000428,VAN -81" {
+		t.Errorf("ErrorCodes = %s", got)
+	}
+
+	if sum.DriverFailureCount != 12 {
+		t.Errorf("DriverFailureCount = %d, want 12", sum.DriverFailureCount)
+	}
+	if len(sum.DriverFailures) != maxLogDriverFailures {
+		t.Fatalf("DriverFailures not capped: %d lines", len(sum.DriverFailures))
+	}
+	if last := sum.DriverFailures[len(sum.DriverFailures)-1]; !strings.HasSuffix(last, "giving up") {
+		t.Errorf("last driver failure = %q", last)
+	}
+}
+
+func TestAnalyzeVanguardLogsNotInstalled(t *testing.T) {
+	sum, err := AnalyzeVanguardLogs("")
+	if err != nil || sum.Found {
+		t.Fatalf("got %+v, %v; want an empty summary", sum, err)
+	}
+
+	sum, err = AnalyzeVanguardLogs(t.TempDir())
+	if err != nil || sum.Found {
+		t.Fatalf("empty install dir: got %+v, %v", sum, err)
+	}
+}
+
+func TestIsSessionStart(t *testing.T) {
+	for line, want := range map[string]bool{
+		"2026-03-02 21:00:00 [INFO] Service start":          true,
+		"[2026-03-02 21:00:00.120] [vgc] [info] vgc start":  true,
+		"03/02/2026 21:00:00 Starting vgc":                  true,
+		"2026-03-02 21:00:02 vgk service start failed":      false,
+		"2026-03-02 21:00:02 [ERROR] Service start failed":  false,
+		"2026-03-02 21:00:02 [INFO] waiting for vgc start":  false,
+		"2026-03-02 21:00:02 [INFO] session started by vgc": true,
+	} {
+		if got := isSessionStart(strings.ToLower(line)); got != want {
+			t.Errorf("isSessionStart(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestParseVanguardLogEmpty(t *testing.T) {
+	sum, err := ParseVanguardLog(strings.NewReader("\ufeff\r\n\r\n"))
+	if err != nil || sum.Found {
+		t.Fatalf("got %+v, %v", sum, err)
+	}
+}
@@ -0,0 +1,35 @@
+package system
+
+// Runtime state of Vanguard, derived from the services and file/boot times.
+// The classic support case is "installed but requires restart": vgk.sys is on
+// disk but was written after the last boot, so the driver is not loaded yet.
+
+const (
+	VanguardStateNotInstalled    = "NotInstalled"
+	VanguardStateRestartRequired = "RestartRequired" // installed/updated after the last boot
+	VanguardStateDriverNotLoaded = "DriverNotLoaded" // vgk present since before boot but not loaded
+	VanguardStateRunning         = "Running"         // vgk loaded; vgc may be stopped until the Riot Client starts it
+	VanguardStateUnknown         = "Unknown"
+)
+
+// VanguardRuntimeState returns the state and a one-line guidance for the user.
+func VanguardRuntimeState(vi VanguardInfo) (string, string) {
+	if !vi.Installed {
+		return VanguardStateNotInstalled, "Install Riot Vanguard from the Riot Client, then restart the PC."
+	}
+	if !vi.VGK.Exists && !vi.DriverPresent {
+		return VanguardStateUnknown, ""
+	}
+
+	if !vi.VGK.Running {
+		if !vi.DriverTime.IsZero() && !vi.LastBoot.IsZero() && vi.DriverTime.After(vi.LastBoot) {
+			return VanguardStateRestartRequired,
+				"Vanguard was installed or updated after the last boot: restart the PC (use Restart, not Shut down, with Fast Startup on)."
+		}
+		return VanguardStateDriverNotLoaded,
+			"vgk is installed but did not load at boot: check the vgk configuration and Vanguard logs, or reinstall Vanguard."
+	}
+
+	// vgc is Manual-start: the Riot Client starts it, so stopped is normal.
+	return VanguardStateRunning, ""
+}
@@ -0,0 +1,42 @@
+package system
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVanguardRuntimeState(t *testing.T) {
+	boot := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	installed := func(vgk, vgc bool, driverTime time.Time) VanguardInfo {
+		return VanguardInfo{
+			Installed:     true,
+			DriverPresent: true,
+			VGK:           ServiceStatus{Exists: true, Running: vgk},
+			VGC:           ServiceStatus{Exists: true, Running: vgc},
+			DriverTime:    driverTime,
+			LastBoot:      boot,
+		}
+	}
+	noTimes := installed(false, false, time.Time{})
+	noTimes.LastBoot = time.Time{}
+
+	for _, tc := range []struct {
+		name     string
+		vi       VanguardInfo
+		want     string
+		wantHint bool
+	}{
+		{"not installed", VanguardInfo{}, VanguardStateNotInstalled, true},
+		{"no vgk at all", VanguardInfo{Installed: true}, VanguardStateUnknown, false},
+		{"driver newer than the last boot", installed(false, false, boot.Add(time.Hour)), VanguardStateRestartRequired, true},
+		{"driver older and not loaded", installed(false, false, boot.Add(-24*time.Hour)), VanguardStateDriverNotLoaded, true},
+		{"times unknown", noTimes, VanguardStateDriverNotLoaded, true},
+		{"vgk running, vgc stopped", installed(true, false, boot.Add(-time.Hour)), VanguardStateRunning, false},
+		{"running", installed(true, true, boot.Add(-time.Hour)), VanguardStateRunning, false},
+	} {
+		state, hint := VanguardRuntimeState(tc.vi)
+		if state != tc.want || (hint != "") != tc.wantHint {
+			t.Errorf("%s: %s %q, want %s (hint %v)", tc.name, state, hint, tc.want, tc.wantHint)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package system
+
+// Signature check of the Vanguard binaries. vgc.exe is signed by Riot Games;
+// vgk.sys carries Microsoft's attestation signature (Windows Hardware
+// Compatibility Publisher) and usually a nested Riot signature.
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SigStatusRiot      = "SignedByRiot"
+	SigStatusMicrosoft = "SignedByMicrosoft" // driver attestation signature
+	SigStatusOther     = "OtherSigner"
+	SigStatusUntrusted = "Untrusted" // signature intact, chain does not reach a trusted root
+	SigStatusInvalid   = "Invalid"   // file modified after signing or corrupt signature
+	SigStatusUnsigned  = "Unsigned"
+	SigStatusMissing   = "Missing"
+)
+
+// CheckBinarySignature verifies path against roots (nil: system store).
+func CheckBinarySignature(path string, roots *x509.CertPool) BinarySignature {
+	bs := BinarySignature{File: filepath.Base(path)}
+
+	sig, err := ReadPESignature(path)
+	switch {
+	case os.IsNotExist(err):
+		bs.Status = SigStatusMissing
+		return bs
+	case err != nil:
+		bs.Status = SigStatusInvalid
+		bs.Detail = err.Error()
+		return bs
+	case !sig.Signed:
+		bs.Status = SigStatusUnsigned
+		return bs
+	}
+
+	// Best verdict over the primary and nested signatures.
+	rank := map[string]int{SigStatusRiot: 5, SigStatusMicrosoft: 4, SigStatusOther: 3, SigStatusUntrusted: 2, SigStatusInvalid: 1}
+	for _, s := range append([]PESignature{sig}, sig.Nested...) {
+		st, detail := SigStatusInvalid, s.Error
+		if s.Valid {
+			if err := VerifySignerChain(s, roots); err != nil {
+				st, detail = SigStatusUntrusted, err.Error()
+			} else {
+				st, detail = signerStatus(s), ""
+			}
+		}
+		if rank[st] > rank[bs.Status] {
+			bs.Status, bs.Detail = st, detail
+			bs.Signer = s.SignerOrg
+			if bs.Signer == "" {
+				bs.Signer = s.Signer
+			}
+		}
+	}
+	return bs
+}
+
+func signerStatus(s PESignature) string {
+	who := strings.ToLower(s.SignerOrg + " " + s.Signer)
+	switch {
+	case strings.Contains(who, "riot games"):
+		return SigStatusRiot
+	case strings.Contains(who, "microsoft"):
+		return SigStatusMicrosoft
+	}
+	return SigStatusOther
+}
+
+// SignatureOK reports whether a Vanguard binary carries an acceptable
+// signature: Riot's, or Microsoft's attestation signature on vgk.sys. Any
+// Microsoft-signed program renamed to vgc.exe would pass otherwise.
+func (bs BinarySignature) SignatureOK() bool {
+	switch bs.Status {
+	case SigStatusRiot:
+		return true
+	case SigStatusMicrosoft:
+		return strings.EqualFold(bs.File, "vgk.sys")
+	}
+	return false
+}
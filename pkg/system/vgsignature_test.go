@@ -0,0 +1,98 @@
+package system
+
+import (
+	"crypto/x509"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTemp(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestCheckBinarySignature(t *testing.T) {
+	signed := readPEFixture(t, "signed.exe")
+
+	// Intact signature, but the chain cannot reach an (empty) root store.
+	bs := CheckBinarySignature(writeTemp(t, "vgc.exe", signed), x509.NewCertPool())
+	if bs.Status != SigStatusUntrusted || bs.Signer != "WireGuard LLC" || bs.SignatureOK() {
+		t.Errorf("empty root store: %+v", bs)
+	}
+
+	if bs := CheckBinarySignature(filepath.Join(t.TempDir(), "vgk.sys"), nil); bs.Status != SigStatusMissing {
+		t.Errorf("missing file: %+v", bs)
+	}
+
+	unsigned := append([]byte(nil), signed[:0x2a00]...) // drop the certificate table
+	binary.LittleEndian.PutUint64(unsigned[optionalHeaderOffset(unsigned)+96+peDirSecurity*8:], 0)
+	if bs := CheckBinarySignature(writeTemp(t, "vgc.exe", unsigned), nil); bs.Status != SigStatusUnsigned {
+		t.Errorf("unsigned: %+v", bs)
+	}
+}
+
+// Truncated or corrupted binaries report an invalid signature instead of panicking.
+func TestCheckBinarySignatureCorrupt(t *testing.T) {
+	signed := readPEFixture(t, "signed.exe")
+
+	badHeaders := append([]byte(nil), signed...)
+	binary.LittleEndian.PutUint32(badHeaders[optionalHeaderOffset(badHeaders)+60:], 0x40)
+
+	tampered := append([]byte(nil), signed...)
+	tampered[0x400] ^= 0xFF
+
+	for name, data := range map[string][]byte{
+		"truncated header":    signed[:0x120],
+		"truncated cert":      signed[:len(signed)-0x100],
+		"small SizeOfHeaders": badHeaders,
+		"tampered section":    tampered,
+		"not a PE":            []byte("MZ this is not a PE file"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			bs := CheckBinarySignature(writeTemp(t, "vgk.sys", data), x509.NewCertPool())
+			if bs.Status != SigStatusInvalid || bs.Detail == "" {
+				t.Fatalf("got %+v, want %s with a detail", bs, SigStatusInvalid)
+			}
+		})
+	}
+}
+
+// A valid signature from anyone but Riot does not pass for vgc.exe.
+func TestSignatureOKSigner(t *testing.T) {
+	path := writeTemp(t, "vgc.exe", readPEFixture(t, "signed.exe"))
+	sig, err := ReadPESignature(path)
+	if err != nil || len(sig.Certs) < 2 {
+		t.Fatalf("fixture: %v, %d certs", err, len(sig.Certs))
+	}
+	roots := x509.NewCertPool() // trust the embedded issuers
+	for _, c := range sig.Certs[1:] {
+		roots.AddCert(c)
+	}
+	bs := CheckBinarySignature(path, roots)
+	if bs.Status != SigStatusOther || bs.SignatureOK() {
+		t.Errorf("WireGuard-signed vgc.exe: %+v, ok %v", bs, bs.SignatureOK())
+	}
+
+	for _, tt := range []struct {
+		file, status string
+		want         bool
+	}{
+		{"vgc.exe", SigStatusRiot, true},
+		{"vgc.exe", SigStatusMicrosoft, false},
+		{"vgk.sys", SigStatusMicrosoft, true},
+		{"VGK.SYS", SigStatusMicrosoft, true},
+		{"vgk.sys", SigStatusRiot, true},
+		{"vgk.sys", SigStatusOther, false},
+		{"vgk.sys", SigStatusUntrusted, false},
+	} {
+		if got := (BinarySignature{File: tt.file, Status: tt.status}).SignatureOK(); got != tt.want {
+			t.Errorf("%s %s: SignatureOK = %v, want %v", tt.file, tt.status, got, tt.want)
+		}
+	}
+}
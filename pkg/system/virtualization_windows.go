@@ -14,7 +14,13 @@ type psVirt struct {
 }
 
 type psDeviceGuard struct {
-	VirtualizationBasedSecurityStatus int `json:"VirtualizationBasedSecurityStatus"`
+	VirtualizationBasedSecurityStatus            int   `json:"VirtualizationBasedSecurityStatus"`
+	SecurityServicesConfigured                   []int `json:"SecurityServicesConfigured"`
+	SecurityServicesRunning                      []int `json:"SecurityServicesRunning"`
+	RequiredSecurityProperties                   []int `json:"RequiredSecurityProperties"`
+	AvailableSecurityProperties                  []int `json:"AvailableSecurityProperties"`
+	CodeIntegrityPolicyEnforcementStatus         int   `json:"CodeIntegrityPolicyEnforcementStatus"`
+	UsermodeCodeIntegrityPolicyEnforcementStatus int   `json:"UsermodeCodeIntegrityPolicyEnforcementStatus"`
 }
 
 func GetVirtualizationInfo() (VirtualizationInfo, error) {
@@ -41,8 +47,16 @@ func GetVirtualizationInfo() (VirtualizationInfo, error) {
 		}
 	}
 
-	// Hyper-V feature state (best-effort)
-	{
+	// Hypervisor features and third-party hypervisors. Get-WindowsOptionalFeature
+	// needs admin, so it is only the fallback for the Hyper-V state.
+	inv, ierr := GetHypervisorInventory()
+	vi.Hypervisors = inv
+	if err == nil {
+		err = ierr
+	}
+	if f, ok := inv.Feature("Microsoft-Hyper-V-All"); ierr == nil && ok {
+		vi.HyperVEnabled = f.Enabled
+	} else {
 		state := runPSOneLine("(Get-WindowsOptionalFeature -Online -FeatureName Microsoft-Hyper-V-All).State")
 		vi.HyperVEnabled = strings.Contains(strings.ToLower(state), "enabled")
 	}
@@ -52,7 +66,9 @@ func GetVirtualizationInfo() (VirtualizationInfo, error) {
 		script := strings.Join([]string{
 			"$ErrorActionPreference='SilentlyContinue';",
 			"$d = Get-CimInstance -Namespace root\\Microsoft\\Windows\\DeviceGuard -ClassName Win32_DeviceGuard |",
-			"     Select-Object -First 1 VirtualizationBasedSecurityStatus;",
+			"     Select-Object -First 1 VirtualizationBasedSecurityStatus, SecurityServicesConfigured, SecurityServicesRunning,",
+			"       RequiredSecurityProperties, AvailableSecurityProperties,",
+			"       CodeIntegrityPolicyEnforcementStatus, UsermodeCodeIntegrityPolicyEnforcementStatus;",
 			"$d | ConvertTo-Json -Compress",
 		}, " ")
 		cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-Command", script)
@@ -62,6 +78,13 @@ func GetVirtualizationInfo() (VirtualizationInfo, error) {
 			var dg psDeviceGuard
 			if j := json.Unmarshal(bytes.TrimSpace(out.Bytes()), &dg); j == nil {
 				vi.VBS_Enabled = dg.VirtualizationBasedSecurityStatus != 0
+				vi.VBSStatus = VBSStatusName(dg.VirtualizationBasedSecurityStatus)
+				vi.SecurityServicesConfigured = mapNames(dg.SecurityServicesConfigured, SecurityServiceName)
+				vi.SecurityServicesRunning = mapNames(dg.SecurityServicesRunning, SecurityServiceName)
+				vi.RequiredSecurityProperties = mapNames(dg.RequiredSecurityProperties, SecurityPropertyName)
+				vi.AvailableSecurityProperties = mapNames(dg.AvailableSecurityProperties, SecurityPropertyName)
+				vi.CodeIntegrityPolicy = CIPolicyStatusName(dg.CodeIntegrityPolicyEnforcementStatus)
+				vi.UserModeCodeIntegrityPolicy = CIPolicyStatusName(dg.UsermodeCodeIntegrityPolicyEnforcementStatus)
 			}
 		}
 	}
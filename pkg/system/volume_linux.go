@@ -0,0 +1,14 @@
+//go:build linux
+
+package system
+
+import "syscall"
+
+// volumeFreeBytes returns the space available to the user on the volume of path.
+func volumeFreeBytes(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), nil
+}
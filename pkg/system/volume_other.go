@@ -0,0 +1,10 @@
+//go:build !windows && !linux
+
+package system
+
+import "errors"
+
+// volumeFreeBytes is not implemented on this platform.
+func volumeFreeBytes(path string) (uint64, error) {
+	return 0, errors.New("free space check not supported on this platform")
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package system
+
+import "golang.org/x/sys/windows"
+
+// volumeFreeBytes returns the space available to the user on the volume of path.
+func volumeFreeBytes(path string) (uint64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var free, total, totalFree uint64
+	err = windows.GetDiskFreeSpaceEx(p, &free, &total, &totalFree)
+	return free, err
+}